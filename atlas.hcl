@@ -3,7 +3,8 @@ data "external_schema" "gorm" {
     "go",
     "run",
     "-mod=mod",
-    "./cmd/loader",
+    "./cmd/app",
+    "schema",
   ]
 }
 
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"test-elabram/internal/cache"
+	"test-elabram/internal/config"
+	"test-elabram/internal/database"
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/notifier"
+	"test-elabram/internal/repository"
+	"test-elabram/internal/storage"
+	"test-elabram/internal/usecase"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// app holds the dependencies shared by every command that talks to the
+// database.
+type app struct {
+	cfg   *config.Config
+	db    *gorm.DB
+	cache *cache.RedisCache
+
+	categoryUsecase       domain.CategoryUsecase
+	productUsecase        domain.ProductUsecase
+	productImportUsecase  domain.ProductImportUsecase
+	productVariantUsecase domain.ProductVariantUsecase
+	productMediaUsecase   domain.ProductMediaUsecase
+	tagUsecase            domain.TagUsecase
+	attributeUsecase      domain.CategoryAttributeUsecase
+	translationUsecase    domain.TranslationUsecase
+	stockMovementUsecase  domain.StockMovementUsecase
+	warehouseUsecase      domain.WarehouseUsecase
+	reservationUsecase    domain.ReservationUsecase
+	stockAlertUsecase     domain.StockAlertUsecase
+	priceChangeUsecase    domain.PriceChangeUsecase
+	priceListUsecase      domain.PriceListUsecase
+}
+
+func newApp() (*app, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := database.Open(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(config.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("load time zone: %w", err)
+	}
+
+	// Initialize Redis Cache
+	redisCache := cache.NewRedisCache(cfg.RedisURL)
+
+	// Initialize Repository
+	categoryRepo := repository.NewCategoryRepository(db)
+	productRepo := repository.NewProductRepository(db)
+	productVariantRepo := repository.NewProductVariantRepository(db)
+	productMediaRepo := repository.NewProductMediaRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	attributeRepo := repository.NewCategoryAttributeRepository(db)
+	translationRepo := repository.NewTranslationRepository(db)
+	validationRepo := repository.NewValidationRepository(db)
+	stockMovementRepo := repository.NewStockMovementRepository(db)
+	warehouseRepo := repository.NewWarehouseRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	stockAlertRepo := repository.NewStockAlertRepository(db)
+	priceChangeRepo := repository.NewPriceChangeRepository(db)
+	priceListRepo := repository.NewPriceListRepository(db)
+
+	// Initialize Low-Stock Alert Notifiers
+	notifiers := []domain.StockAlertNotifier{notifier.NewLogNotifier()}
+	if cfg.AlertWebhookURL != "" {
+		notifiers = append(notifiers, notifier.NewWebhookNotifier(cfg.AlertWebhookURL))
+	}
+	if cfg.SMTPAddr != "" && len(cfg.AlertEmailTo) > 0 {
+		notifiers = append(notifiers, notifier.NewSMTPNotifier(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.AlertEmailFrom, cfg.AlertEmailTo))
+	}
+
+	// Initialize Media Storage
+	mediaStorage, err := storage.NewLocalStorage(cfg.MediaDir, cfg.MediaBaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("open media storage: %w", err)
+	}
+
+	// Initialize Usecase
+	helper.RegisterDatabaseRules(validationRepo)
+	requestValidator := helper.NewRequestValidator()
+	stockAlertUsecase := usecase.NewStockAlertUsecase(stockAlertRepo, notifiers)
+	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo, requestValidator, translationRepo)
+	productUsecase := usecase.NewProductUsecase(productRepo, priceListRepo, redisCache, requestValidator, stockAlertUsecase, mediaStorage, tagRepo, attributeRepo, translationRepo)
+	productImportUsecase := usecase.NewProductImportUsecase(productRepo, categoryRepo, attributeRepo, redisCache, requestValidator)
+	productVariantUsecase := usecase.NewProductVariantUsecase(productVariantRepo, redisCache, stockAlertUsecase)
+	productMediaUsecase := usecase.NewProductMediaUsecase(productMediaRepo, mediaStorage)
+	tagUsecase := usecase.NewTagUsecase(tagRepo, requestValidator)
+	attributeUsecase := usecase.NewCategoryAttributeUsecase(attributeRepo, requestValidator)
+	translationUsecase := usecase.NewTranslationUsecase(translationRepo)
+	stockMovementUsecase := usecase.NewStockMovementUsecase(stockMovementRepo, redisCache, stockAlertUsecase, requestValidator)
+	warehouseUsecase := usecase.NewWarehouseUsecase(warehouseRepo, redisCache)
+	reservationUsecase := usecase.NewReservationUsecase(reservationRepo, redisCache, stockAlertUsecase)
+	priceChangeUsecase := usecase.NewPriceChangeUsecase(priceChangeRepo, redisCache, location, requestValidator)
+	priceListUsecase := usecase.NewPriceListUsecase(priceListRepo, requestValidator)
+
+	return &app{
+		cfg:                   cfg,
+		db:                    db,
+		cache:                 redisCache,
+		categoryUsecase:       categoryUsecase,
+		productUsecase:        productUsecase,
+		productImportUsecase:  productImportUsecase,
+		productVariantUsecase: productVariantUsecase,
+		productMediaUsecase:   productMediaUsecase,
+		tagUsecase:            tagUsecase,
+		attributeUsecase:      attributeUsecase,
+		translationUsecase:    translationUsecase,
+		stockMovementUsecase:  stockMovementUsecase,
+		warehouseUsecase:      warehouseUsecase,
+		reservationUsecase:    reservationUsecase,
+		stockAlertUsecase:     stockAlertUsecase,
+		priceChangeUsecase:    priceChangeUsecase,
+		priceListUsecase:      priceListUsecase,
+	}, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/export"
+)
+
+// runExport streams the catalog in the same formats as GET /products/export.
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "ndjson", "output format: csv, ndjson or xlsx")
+	output := fs.String("o", "", "output file (defaults to stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *output == "" {
+		return exportProducts(*format, os.Stdout)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := exportProducts(*format, f); err != nil {
+		f.Close()
+		return err
+	}
+	// A failed close can lose the end of the file.
+	return f.Close()
+}
+
+// exportProducts writes the whole catalog to out in format.
+func exportProducts(format string, out io.Writer) error {
+	w, err := export.NewWriter(format, out)
+	if err != nil {
+		return err
+	}
+
+	a, err := newApp()
+	if err != nil {
+		return err
+	}
+
+	if err := a.productUsecase.ExportProducts(context.Background(), dto.ProductFilterParams{}, w.WriteRow); err != nil {
+		return err
+	}
+	return w.Close()
+}
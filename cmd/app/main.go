@@ -2,68 +2,45 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
-	"test-elabram/internal/cache"
-	"test-elabram/internal/delivery/http"
-	"test-elabram/internal/repository"
-	"test-elabram/internal/usecase"
 
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
+	// Embed the time zone database, so the store's zone loads on hosts
+	// without one.
+	_ "time/tzdata"
 )
 
-func main() {
-	// Load .env file
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file")
-	}
-
-	// Database Connection
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Jakarta",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
-	)
+const usage = `Usage: app <command> [flags]
+
+Commands:
+  serve                  Run the HTTP server
+  migrate up|down|status Apply, roll back or list the migrations in migrations/
+  seed                   Load fixture categories and products
+  export                 Dump the catalog
+  schema                 Print the GORM schema for Atlas
+`
+
+var commands = map[string]func(args []string) error{
+	"serve":   runServe,
+	"migrate": runMigrate,
+	"seed":    runSeed,
+	"export":  runExport,
+	"schema":  runSchema,
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
 	}
 
-	// Initialize Redis Cache
-	redisAddr := os.Getenv("REDIS_URL")
-	if redisAddr == "" {
-		redisAddr = "localhost:6379"
+	run, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
 	}
-	redisCache := cache.NewRedisCache(redisAddr)
 
-	// Initialize Repository
-	categoryRepo := repository.NewCategoryRepository(db)
-	productRepo := repository.NewProductRepository(db)
-
-	// Initialize Usecase
-	categoryUsecase := usecase.NewCategoryUsecase(categoryRepo)
-	productUsecase := usecase.NewProductUsecase(productRepo, redisCache)
-
-	// Initialize Gin Engine
-	r := gin.Default()
-
-	// Initialize Delivery (Handler)
-	http.NewCategoryHandler(r, categoryUsecase)
-	http.NewProductHandler(r, productUsecase)
-
-	// Run Server
-	port := os.Getenv("SERVER_PORT")
-	if port == "" {
-		port = "8080"
-	}
-	log.Printf("Server starting on port %s...", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if err := run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[1], err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"test-elabram/internal/config"
+	"test-elabram/internal/database"
+	"test-elabram/internal/migration"
+	"test-elabram/internal/repository"
+)
+
+const migrateUsage = `Usage: app migrate [-dir migrations] <up|down|status> [flags]
+
+  up     [-baseline VERSION]  Apply pending migrations. With -baseline, mark
+                              migrations up to VERSION as applied first
+                              (databases migrated with the Atlas CLI).
+  down   [-steps N]           Roll back the last N applied migrations (default 1)
+  status                      List migrations and when they were applied
+`
+
+func runMigrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dir := fs.String("dir", "migrations", "migration directory")
+	fs.Usage = func() { fmt.Fprint(os.Stderr, migrateUsage) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	db, err := database.Open(cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	migrator := migration.NewMigrator(db, os.DirFS(*dir), map[string]migration.Step{
+		// The add_slugs migration derives slugs in SQL, which only
+		// approximates slug.Make.
+		"20261019090000": repository.BackfillSlugs,
+	})
+
+	sub, subArgs := fs.Arg(0), fs.Args()[1:]
+	switch sub {
+	case "up":
+		upFlags := flag.NewFlagSet("migrate up", flag.ExitOnError)
+		baseline := upFlags.String("baseline", "", "mark migrations up to this version as applied")
+		if err := upFlags.Parse(subArgs); err != nil {
+			return err
+		}
+		if *baseline != "" {
+			if err := migrator.Baseline(ctx, *baseline); err != nil {
+				return err
+			}
+			log.Printf("[MIGRATE] Baselined at %s", *baseline)
+		}
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Printf("[MIGRATE] Applied %s", m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			log.Printf("[MIGRATE] No pending migrations")
+		}
+		return nil
+
+	case "down":
+		downFlags := flag.NewFlagSet("migrate down", flag.ExitOnError)
+		steps := downFlags.Int("steps", 1, "number of migrations to roll back")
+		if err := downFlags.Parse(subArgs); err != nil {
+			return err
+		}
+		if *steps < 1 {
+			return errors.New("steps must be at least 1")
+		}
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, m := range reverted {
+			log.Printf("[MIGRATE] Rolled back %s", m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			log.Printf("[MIGRATE] Nothing to roll back")
+		}
+		return nil
+
+	case "status":
+		migrations, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			state := "pending"
+			if m.AppliedAt != nil {
+				state = "applied " + m.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%-16s %-40s %s\n", m.Version, m.Description, state)
+		}
+		return nil
+
+	default:
+		fs.Usage()
+		os.Exit(2)
+	}
+	return nil
+}
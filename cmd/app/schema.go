@@ -0,0 +1,31 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"test-elabram/internal/domain"
+
+	"ariga.io/atlas-provider-gorm/gormschema"
+)
+
+// schemaExtensions are the extensions the models' indexes depend on. They
+// are created ahead of the tables so the dev database can load the schema.
+const schemaExtensions = "CREATE EXTENSION IF NOT EXISTS pg_trgm;\n"
+
+// runSchema prints the desired schema for Atlas. It must not touch the
+// database or write anything else to stdout.
+func runSchema(args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	stmts, err := gormschema.New("postgres").Load(&domain.Category{}, &domain.Product{}, &domain.ProductVariant{}, &domain.Warehouse{}, &domain.StockLevel{}, &domain.StockMovement{}, &domain.Reservation{}, &domain.ReservationItem{}, &domain.ProductMedia{}, &domain.Tag{}, &domain.CategoryAttribute{}, &domain.ProductTranslation{}, &domain.CategoryTranslation{}, &domain.SlugRedirect{}, &domain.StockAlert{}, &domain.PriceChange{}, &domain.PriceList{}, &domain.PriceListItem{}, &domain.ExchangeRate{})
+	if err != nil {
+		return fmt.Errorf("failed to load gorm schema: %w", err)
+	}
+	_, err = io.WriteString(os.Stdout, schemaExtensions+stmts)
+	return err
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/money"
+)
+
+//go:embed fixtures/catalog.json
+var defaultFixtures []byte
+
+type fixtures struct {
+	Categories []struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Parent      string `json:"parent"`
+	} `json:"categories"`
+	Products []struct {
+		Name          string `json:"name"`
+		Description   string `json:"description"`
+		Price         int64  `json:"price"` // in the default currency
+		StockQuantity int    `json:"stock_quantity"`
+		IsActive      bool   `json:"is_active"`
+		Category      string `json:"category"`
+	} `json:"products"`
+}
+
+// runSeed loads fixture categories and products. Categories may name a
+// parent listed earlier in the file. Records that already exist by name are
+// left alone, so seeding twice is harmless.
+func runSeed(args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ExitOnError)
+	file := fs.String("file", "", "fixture file (defaults to the bundled catalog)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data := defaultFixtures
+	if *file != "" {
+		var err error
+		if data, err = os.ReadFile(*file); err != nil {
+			return err
+		}
+	}
+	var fx fixtures
+	if err := json.Unmarshal(data, &fx); err != nil {
+		return fmt.Errorf("parse fixtures: %w", err)
+	}
+
+	a, err := newApp()
+	if err != nil {
+		return err
+	}
+	ctx := domain.WithActor(context.Background(), "seed")
+
+	existingCategories, err := a.categoryUsecase.GetAllCategories(ctx, dto.Projection{})
+	if err != nil {
+		return err
+	}
+	categoryIDs := make(map[string]uint, len(existingCategories))
+	for _, c := range existingCategories {
+		categoryIDs[c.Name] = c.ID
+	}
+
+	for _, fc := range fx.Categories {
+		if _, ok := categoryIDs[fc.Name]; ok {
+			continue
+		}
+		category := domain.Category{
+			Name:        fc.Name,
+			Description: fc.Description,
+		}
+		if fc.Parent != "" {
+			parentID, ok := categoryIDs[fc.Parent]
+			if !ok {
+				return fmt.Errorf("category %q references unknown parent %q; list parents first", fc.Name, fc.Parent)
+			}
+			category.ParentID = &parentID
+		}
+		if err := a.categoryUsecase.CreateCategory(ctx, &category); err != nil {
+			return fmt.Errorf("create category %q: %w", fc.Name, err)
+		}
+		categoryIDs[category.Name] = category.ID
+		log.Printf("[SEED] Created category %q", category.Name)
+	}
+
+	existingProducts, err := a.productUsecase.GetAllProducts(ctx)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]bool, len(existingProducts))
+	for _, p := range existingProducts {
+		seen[fmt.Sprintf("%d/%s", p.CategoryID, p.Name)] = true
+	}
+
+	for _, fp := range fx.Products {
+		categoryID, ok := categoryIDs[fp.Category]
+		if !ok {
+			return fmt.Errorf("product %q references unknown category %q", fp.Name, fp.Category)
+		}
+		if seen[fmt.Sprintf("%d/%s", categoryID, fp.Name)] {
+			continue
+		}
+		product := domain.Product{
+			Name:          fp.Name,
+			Description:   fp.Description,
+			Price:         money.New(fp.Price, money.DefaultCurrency),
+			StockQuantity: fp.StockQuantity,
+			IsActive:      fp.IsActive,
+			CategoryID:    categoryID,
+		}
+		if err := a.productUsecase.CreateProduct(ctx, &product); err != nil {
+			return fmt.Errorf("create product %q: %w", fp.Name, err)
+		}
+		log.Printf("[SEED] Created product %q", product.Name)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"test-elabram/internal/delivery/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	reservationSweepInterval   = time.Minute
+	stockAlertEvaluateInterval = 5 * time.Minute
+	priceSchedulerInterval     = time.Minute
+)
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	a, err := newApp()
+	if err != nil {
+		return err
+	}
+
+	// Initialize Gin Engine. ContextWithFallback lets usecases read values
+	// that middleware stores in the request context, such as the actor and
+	// the locale.
+	r := gin.Default()
+	r.ContextWithFallback = true
+	r.Use(http.ActorMiddleware(), http.LocaleMiddleware())
+
+	// Initialize Delivery (Handler)
+	http.NewCategoryHandler(r, a.categoryUsecase)
+	http.NewProductHandler(r, a.productUsecase)
+	http.NewProductImportHandler(r, a.productImportUsecase)
+	http.NewProductVariantHandler(r, a.productVariantUsecase)
+	http.NewProductMediaHandler(r, a.productMediaUsecase)
+	http.NewTagHandler(r, a.tagUsecase)
+	http.NewCategoryAttributeHandler(r, a.attributeUsecase)
+	http.NewTranslationHandler(r, a.translationUsecase)
+	http.NewStockMovementHandler(r, a.stockMovementUsecase)
+	http.NewWarehouseHandler(r, a.warehouseUsecase)
+	http.NewReservationHandler(r, a.reservationUsecase)
+	http.NewStockAlertHandler(r, a.stockAlertUsecase)
+	http.NewPriceChangeHandler(r, a.priceChangeUsecase)
+	http.NewPriceListHandler(r, a.priceListUsecase)
+
+	// Expire stock reservation holds in the background
+	go a.reservationUsecase.RunSweeper(context.Background(), reservationSweepInterval)
+
+	// Evaluate low-stock alerts in the background
+	go a.stockAlertUsecase.Run(context.Background(), stockAlertEvaluateInterval)
+
+	// Apply scheduled price changes in the background
+	go a.priceChangeUsecase.RunScheduler(context.Background(), priceSchedulerInterval)
+
+	// Run Server
+	log.Printf("Server starting on port %s...", a.cfg.ServerPort)
+	return r.Run(":" + a.cfg.ServerPort)
+}
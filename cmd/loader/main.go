@@ -1,19 +0,0 @@
-package main
-
-import (
-	"fmt"
-	"io"
-	"os"
-	"test-elabram/internal/domain"
-
-	"ariga.io/atlas-provider-gorm/gormschema"
-)
-
-func main() {
-	stmts, err := gormschema.New("postgres").Load(&domain.Category{}, &domain.Product{})
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to load gorm schema: %v\n", err)
-		os.Exit(1)
-	}
-	io.WriteString(os.Stdout, stmts)
-}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// TimeZone is the store's time zone. The database session uses it, and
+// scheduled times given without a UTC offset are read in it.
+const TimeZone = "Asia/Jakarta"
+
+type Config struct {
+	DBHost     string
+	DBUser     string
+	DBPassword string
+	DBName     string
+	DBPort     string
+	ServerPort string
+	RedisURL   string
+
+	// Low-stock alert notifiers. The webhook and e-mail notifiers are only
+	// enabled when their address is set; alerts are always logged.
+	AlertWebhookURL string
+	SMTPAddr        string
+	SMTPUsername    string
+	SMTPPassword    string
+	AlertEmailFrom  string
+	AlertEmailTo    []string
+
+	// Product media are kept under MediaDir and served from MediaBaseURL,
+	// which may point at a CDN in front of the /media route.
+	MediaDir     string
+	MediaBaseURL string
+}
+
+// Load reads the configuration from the environment. A .env file in the
+// working directory is loaded first when present, so deployments that inject
+// variables directly do not need one.
+func Load() (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("load .env file: %w", err)
+	}
+
+	return &Config{
+		DBHost:     os.Getenv("DB_HOST"),
+		DBUser:     os.Getenv("DB_USER"),
+		DBPassword: os.Getenv("DB_PASSWORD"),
+		DBName:     os.Getenv("DB_NAME"),
+		DBPort:     os.Getenv("DB_PORT"),
+		ServerPort: getEnv("SERVER_PORT", "8080"),
+		RedisURL:   getEnv("REDIS_URL", "localhost:6379"),
+
+		AlertWebhookURL: os.Getenv("ALERT_WEBHOOK_URL"),
+		SMTPAddr:        os.Getenv("SMTP_ADDR"),
+		SMTPUsername:    os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:    os.Getenv("SMTP_PASSWORD"),
+		AlertEmailFrom:  getEnv("ALERT_EMAIL_FROM", "alerts@localhost"),
+		AlertEmailTo:    splitList(os.Getenv("ALERT_EMAIL_TO")),
+
+		MediaDir:     getEnv("MEDIA_DIR", "./storage/media"),
+		MediaBaseURL: getEnv("MEDIA_BASE_URL", "/media"),
+	}, nil
+}
+
+func (c *Config) DSN() string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=%s",
+		c.DBHost,
+		c.DBUser,
+		c.DBPassword,
+		c.DBName,
+		c.DBPort,
+		TimeZone,
+	)
+}
+
+// splitList splits a comma-separated value, dropping empty entries.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func getEnv(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
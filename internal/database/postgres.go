@@ -0,0 +1,19 @@
+package database
+
+import (
+	"fmt"
+	"test-elabram/internal/config"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// Open connects to Postgres. Driver errors such as unique violations are
+// translated to gorm's portable errors (e.g. gorm.ErrDuplicatedKey).
+func Open(cfg *config.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,50 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"test-elabram/internal/dto"
+)
+
+// Project trims a record, or a list of records, to the fields and relations
+// of a sparse response; relations names the keys that are relations rather
+// than fields. A full projection returns v unchanged.
+func Project(v interface{}, proj dto.Projection, relations []string) (interface{}, error) {
+	if !proj.Sparse() {
+		return v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := func(record map[string]json.RawMessage) {
+		for key := range record {
+			if slices.Contains(relations, key) {
+				if !proj.Includes(key) {
+					delete(record, key)
+				}
+			} else if !proj.HasField(key) {
+				delete(record, key)
+			}
+		}
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var records []map[string]json.RawMessage
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			keep(record)
+		}
+		return records, nil
+	}
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	keep(record)
+	return record, nil
+}
@@ -1,24 +1,149 @@
 package helper
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/money"
+
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
-func MsgForTag(fe validator.FieldError) string {
-	switch fe.Tag() {
-	case "required":
-		return "This field is required"
-	case "gt":
-		return "Must be greater than " + fe.Param()
-	case "gte":
-		return "Must be greater than or equal to " + fe.Param()
-	case "email":
-		return "Must be a valid email address"
-	case "min":
-		return "Must be at least " + fe.Param() + " characters"
-	case "max":
-		return "Must be at most " + fe.Param() + " characters"
-	default:
-		return "Invalid value"
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(fieldName)
+		_ = v.RegisterValidation("currency", validateCurrency)
+		_ = v.RegisterValidation("price", validatePrice)
+		_ = v.RegisterValidation("rate", validateRate)
+		_ = v.RegisterValidation("attribute_name", validateAttributeName)
+		_ = v.RegisterValidation("ledger", validateLedger, true)
+	}
+}
+
+// fieldName names fields in validation errors as clients send them: by
+// their JSON name, or their query parameter for query structs.
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+func validateCurrency(fl validator.FieldLevel) bool {
+	_, ok := money.Exponent(fl.Field().String())
+	return ok
+}
+
+// validatePrice checks that a decimal amount is greater than 0 and has at
+// most the decimal places of the currency in the sibling field named by the
+// tag's parameter, or of the default currency when that is empty. An unknown
+// currency is left to the currency tag.
+func validatePrice(fl validator.FieldLevel) bool {
+	currency := money.DefaultCurrency
+	if f := fl.Parent().FieldByName(fl.Param()); f.IsValid() && f.String() != "" {
+		currency = f.String()
+	}
+	m, err := money.Parse(fl.Field().String(), currency)
+	if errors.Is(err, money.ErrUnknownCurrency) {
+		return true
+	}
+	return err == nil && m.Amount > 0
+}
+
+// rateRegexp matches the decimals an exchange rate column can hold.
+var rateRegexp = regexp.MustCompile(`^[0-9]{1,10}(\.[0-9]{1,10})?$`)
+
+func validateRate(fl validator.FieldLevel) bool {
+	rate := fl.Field().String()
+	return rateRegexp.MatchString(rate) && strings.Trim(rate, "0.") != ""
+}
+
+// attributeNameRegexp matches attribute names, which are used as JSON keys
+// in filters.
+var attributeNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+func validateAttributeName(fl validator.FieldLevel) bool {
+	return attributeNameRegexp.MatchString(fl.Field().String())
+}
+
+// validateLedger only accepts a nil pointer. It marks the stock quantities
+// of requests that may not set them, as stock only changes through stock
+// movements.
+func validateLedger(fl validator.FieldLevel) bool {
+	return fl.Field().Kind() == reflect.Pointer && fl.Field().IsNil()
+}
+
+// currencyPrecisions lists the decimal places of each currency, e.g.
+// "IDR: 0, USD: 2".
+func currencyPrecisions() string {
+	var precisions []string
+	for _, code := range money.Currencies() {
+		exp, _ := money.Exponent(code)
+		precisions = append(precisions, fmt.Sprintf("%s: %d", code, exp))
+	}
+	return strings.Join(precisions, ", ")
+}
+
+// FieldErrors renders validation errors by JSON field name, in the language
+// of the locale of ctx.
+func FieldErrors(ctx context.Context, ve validator.ValidationErrors) map[string]string {
+	trans := translatorFor(ctx)
+	fieldErrors := make(map[string]string)
+	for _, fe := range ve {
+		fieldErrors[fe.Field()] = fieldMessage(trans, fe)
+	}
+	return fieldErrors
+}
+
+type requestValidator struct{}
+
+// NewRequestValidator returns a domain.RequestValidator backed by Gin's
+// validator engine, the one ShouldBindJSON uses.
+func NewRequestValidator() domain.RequestValidator {
+	return requestValidator{}
+}
+
+// ValidateStruct also runs the rules of RegisterDatabaseRules, returning
+// the error of a failed lookup.
+func (requestValidator) ValidateStruct(ctx context.Context, obj interface{}) (map[string]string, error) {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil, errors.New("unsupported validator engine")
+	}
+	errs := &ruleErrors{}
+	err := v.StructCtx(context.WithValue(ctx, ruleErrorsKey{}, errs), obj)
+	if errs.err != nil {
+		return nil, errs.err
+	}
+	if err == nil {
+		return nil, nil
+	}
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		return FieldErrors(ctx, ve), nil
+	}
+	return nil, err
+}
+
+func (requestValidator) Message(ctx context.Context, key string, params ...string) string {
+	return Message(ctx, key, params...)
+}
+
+// Message renders the validation message of key, a validation tag or a
+// check made outside the rules, in the locale of ctx.
+func Message(ctx context.Context, key string, params ...string) string {
+	trans := translatorFor(ctx)
+	msg, err := trans.T(key, params...)
+	if err != nil {
+		msg, _ = trans.T("invalid")
 	}
+	return msg
 }
@@ -0,0 +1,240 @@
+package helper
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/locale"
+	"test-elabram/internal/money"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// messages are the validation messages of each locale by key. The key is
+// the validation tag, with a suffix for the comparison tags, whose message
+// depends on what is compared: "-number" for numbers. {0} is the tag's
+// parameter, which is the other field's name for ltefield. The other keys
+// are checks made outside the rules; the messages of the expression_,
+// sort_ and list_ keys start with the quoted expression. Translations must
+// keep the placeholders in order, as the translator fills them in turn.
+var messages = map[string]map[string]string{
+	"en": {
+		"required":          "This field is required",
+		"email":             "Must be a valid email address",
+		"oneof":             "Must be one of {0}",
+		"unique":            "Must not contain duplicates",
+		"price":             "Must be a decimal amount greater than 0 with at most the decimal places of its currency ({0})",
+		"rate":              "Must be a decimal greater than 0 with at most 10 digits before and after the point",
+		"attribute_name":    "Must be a lowercase letter followed by at most 63 lowercase letters, digits or underscores",
+		"ltefield":          "Must not be greater than {0}",
+		"ledger":            "Stock only changes through POST /products/:id/stock-movements",
+		"attribute_unknown": "Not an attribute of the category",
+		"attribute_string":  "Must be a string",
+		"attribute_number":  "Must be a number",
+		"attribute_boolean": "Must be true or false",
+		"category_exists":   "Category does not exist",
+		"unique_name":       "Must be unique within the category",
+		"cell_int":          "Must be an integer",
+		"cell_uint":         "Must be a positive integer",
+		"cell_bool":         "Must be true or false",
+		"row_json":          "Invalid JSON: {0}",
+		"product_exists":    "Product does not exist",
+		"tags_unknown":      "Unknown tags: {0}",
+		"options_required":  "This field is required for enum attributes",
+		"options_excluded":  "Only enum attributes have options",
+		"slug":              "Must contain a letter or digit",
+		"currency_pair":     "Must differ from the base currency",
+		"schedule_time":     "Must be an RFC 3339 or YYYY-MM-DD HH:MM time",
+		"future":            "Must be in the future",
+		"price_bound":       "Must be a decimal amount with at most {0} decimal places in {1}",
+		"batch_rolled_back": "Batch rolled back: {0}",
+		"expression_format": "{0}: must be field:op:value",
+		"expression_field":  "{0}: unknown field; allowed: {1}",
+		"expression_op":     "{0}: {1} does not support {2}; allowed: {3}",
+		"expression_values": "{0}: at most {1} values",
+		"expression_bool":   "{0}: {1} is not true or false",
+		"expression_int":    "{0}: {1} is not an integer",
+		"expression_number": "{0}: {1} is not a number",
+		"expression_time":   "{0}: {1} is not a date (2006-01-02) or RFC 3339 time",
+		"sort_field":        "{0}: cannot sort by {1}; allowed: {2}",
+		"sort_repeated":     "{0}: {1} is listed twice",
+		"list_name":         "{0}: unknown name {1}; allowed: {2}",
+		"list_none":         "{0}: unknown name {1}; none are allowed",
+		"gt-number":         "Must be greater than {0}",
+		"gte-number":        "Must be greater than or equal to {0}",
+		"lt-number":         "Must be less than {0}",
+		"lte-number":        "Must be less than or equal to {0}",
+		"len-number":        "Must be equal to {0}",
+		"invalid":           "Invalid value",
+	},
+	"id": {
+		"required":          "Wajib diisi",
+		"email":             "Harus berupa alamat email yang valid",
+		"oneof":             "Harus salah satu dari {0}",
+		"unique":            "Tidak boleh berisi duplikat",
+		"price":             "Harus berupa jumlah desimal lebih dari 0 dengan jumlah angka desimal paling banyak sesuai mata uangnya ({0})",
+		"rate":              "Harus berupa desimal lebih dari 0 dengan paling banyak 10 digit sebelum dan sesudah titik",
+		"attribute_name":    "Harus berupa huruf kecil diikuti paling banyak 63 huruf kecil, angka, atau garis bawah",
+		"ltefield":          "Tidak boleh lebih besar dari {0}",
+		"ledger":            "Stok hanya bisa diubah melalui POST /products/:id/stock-movements",
+		"attribute_unknown": "Bukan atribut kategori ini",
+		"attribute_string":  "Harus berupa teks",
+		"attribute_number":  "Harus berupa angka",
+		"attribute_boolean": "Harus bernilai true atau false",
+		"category_exists":   "Kategori tidak ada",
+		"unique_name":       "Sudah dipakai produk lain di kategori ini",
+		"cell_int":          "Harus berupa bilangan bulat",
+		"cell_uint":         "Harus berupa bilangan bulat positif",
+		"cell_bool":         "Harus bernilai true atau false",
+		"row_json":          "JSON tidak valid: {0}",
+		"product_exists":    "Produk tidak ada",
+		"tags_unknown":      "Tag tidak dikenal: {0}",
+		"options_required":  "Wajib diisi untuk atribut enum",
+		"options_excluded":  "Hanya atribut enum yang memiliki opsi",
+		"slug":              "Harus berisi huruf atau angka",
+		"currency_pair":     "Harus berbeda dari mata uang dasar",
+		"schedule_time":     "Harus berupa waktu RFC 3339 atau YYYY-MM-DD HH:MM",
+		"future":            "Harus di masa depan",
+		"price_bound":       "Harus berupa jumlah desimal dengan paling banyak {0} angka desimal dalam {1}",
+		"batch_rolled_back": "Batch dibatalkan: {0}",
+		"expression_format": "{0}: harus berbentuk field:op:value",
+		"expression_field":  "{0}: field tidak dikenal; yang diizinkan: {1}",
+		"expression_op":     "{0}: {1} tidak mendukung {2}; yang diizinkan: {3}",
+		"expression_values": "{0}: paling banyak {1} nilai",
+		"expression_bool":   "{0}: {1} bukan true atau false",
+		"expression_int":    "{0}: {1} bukan bilangan bulat",
+		"expression_number": "{0}: {1} bukan angka",
+		"expression_time":   "{0}: {1} bukan tanggal (2006-01-02) atau waktu RFC 3339",
+		"sort_field":        "{0}: tidak bisa diurutkan menurut {1}; yang diizinkan: {2}",
+		"sort_repeated":     "{0}: {1} disebut dua kali",
+		"list_name":         "{0}: nama {1} tidak dikenal; yang diizinkan: {2}",
+		"list_none":         "{0}: nama {1} tidak dikenal; tidak ada yang diizinkan",
+		"gt-number":         "Harus lebih besar dari {0}",
+		"gte-number":        "Harus lebih besar dari atau sama dengan {0}",
+		"lt-number":         "Harus lebih kecil dari {0}",
+		"lte-number":        "Harus lebih kecil dari atau sama dengan {0}",
+		"len-number":        "Harus sama dengan {0}",
+		"invalid":           "Nilai tidak valid",
+	},
+}
+
+// countMessages are the messages of the comparison tags on the length of
+// strings, suffixed "-string", and of lists, suffixed "-items", by plural
+// form. Indonesian nouns have a single form.
+var countMessages = map[string]map[string]map[locales.PluralRule]string{
+	"en": {
+		"gt-string":  {locales.PluralRuleOne: "Must be longer than {0} character", locales.PluralRuleOther: "Must be longer than {0} characters"},
+		"gte-string": {locales.PluralRuleOne: "Must be at least {0} character", locales.PluralRuleOther: "Must be at least {0} characters"},
+		"lt-string":  {locales.PluralRuleOne: "Must be shorter than {0} character", locales.PluralRuleOther: "Must be shorter than {0} characters"},
+		"lte-string": {locales.PluralRuleOne: "Must be at most {0} character", locales.PluralRuleOther: "Must be at most {0} characters"},
+		"len-string": {locales.PluralRuleOne: "Must be exactly {0} character", locales.PluralRuleOther: "Must be exactly {0} characters"},
+		"gt-items":   {locales.PluralRuleOne: "Must have more than {0} item", locales.PluralRuleOther: "Must have more than {0} items"},
+		"gte-items":  {locales.PluralRuleOne: "Must have at least {0} item", locales.PluralRuleOther: "Must have at least {0} items"},
+		"lt-items":   {locales.PluralRuleOne: "Must have fewer than {0} item", locales.PluralRuleOther: "Must have fewer than {0} items"},
+		"lte-items":  {locales.PluralRuleOne: "Must have at most {0} item", locales.PluralRuleOther: "Must have at most {0} items"},
+		"len-items":  {locales.PluralRuleOne: "Must have exactly {0} item", locales.PluralRuleOther: "Must have exactly {0} items"},
+	},
+	"id": {
+		"gt-string":  {locales.PluralRuleOther: "Harus lebih dari {0} karakter"},
+		"gte-string": {locales.PluralRuleOther: "Minimal {0} karakter"},
+		"lt-string":  {locales.PluralRuleOther: "Harus kurang dari {0} karakter"},
+		"lte-string": {locales.PluralRuleOther: "Maksimal {0} karakter"},
+		"len-string": {locales.PluralRuleOther: "Harus tepat {0} karakter"},
+		"gt-items":   {locales.PluralRuleOther: "Harus berisi lebih dari {0} item"},
+		"gte-items":  {locales.PluralRuleOther: "Harus berisi minimal {0} item"},
+		"lt-items":   {locales.PluralRuleOther: "Harus berisi kurang dari {0} item"},
+		"lte-items":  {locales.PluralRuleOther: "Harus berisi maksimal {0} item"},
+		"len-items":  {locales.PluralRuleOther: "Harus berisi tepat {0} item"},
+	},
+}
+
+// comparisonTags maps the comparison tags to the keys of their messages;
+// min and max read like gte and lte.
+var comparisonTags = map[string]string{
+	"gt": "gt", "gte": "gte", "min": "gte", "lt": "lt", "lte": "lte", "max": "lte", "len": "len",
+}
+
+var translators = newTranslators()
+
+// newTranslators loads the messages into a translator per supported locale.
+// The messages are fixed, so a mistake in them panics at startup.
+func newTranslators() *ut.UniversalTranslator {
+	uni := ut.New(id.New(), id.New(), en.New())
+	for _, l := range locale.Supported {
+		trans, found := uni.GetTranslator(l)
+		if !found {
+			panic("no translator for locale " + l)
+		}
+		for key, text := range messages[l] {
+			if err := trans.Add(key, text, false); err != nil {
+				panic(err)
+			}
+		}
+		for key, forms := range countMessages[l] {
+			for rule, text := range forms {
+				if err := trans.AddCardinal(key, text, rule, false); err != nil {
+					panic(err)
+				}
+			}
+		}
+		if err := trans.VerifyTranslations(); err != nil {
+			panic(err)
+		}
+	}
+	return uni
+}
+
+// translatorFor returns the translator of the locale of ctx.
+func translatorFor(ctx context.Context) ut.Translator {
+	trans, _ := translators.GetTranslator(domain.LocaleFromContext(ctx))
+	return trans
+}
+
+// fieldMessage renders the message of a validation error in the language of
+// trans. Unknown tags get a generic message.
+func fieldMessage(trans ut.Translator, fe validator.FieldError) string {
+	var msg string
+	var err error
+	switch tag := fe.Tag(); {
+	case comparisonTags[tag] != "":
+		msg, err = comparisonMessage(trans, comparisonTags[tag], fe)
+	case tag == "oneof":
+		msg, err = trans.T("oneof", strings.Join(strings.Fields(fe.Param()), ", "))
+	case tag == "currency":
+		msg, err = trans.T("oneof", strings.Join(money.Currencies(), ", "))
+	case tag == "price":
+		msg, err = trans.T("price", currencyPrecisions())
+	case tag == "ltefield":
+		msg, err = trans.T("ltefield", fe.Param())
+	default:
+		msg, err = trans.T(tag)
+	}
+	if err != nil {
+		msg, _ = trans.T("invalid")
+	}
+	return msg
+}
+
+// comparisonMessage words a comparison by what is compared: the length of
+// a string, the number of items of a list, or else a value.
+func comparisonMessage(trans ut.Translator, key string, fe validator.FieldError) (string, error) {
+	switch fe.Kind() {
+	case reflect.String:
+		key += "-string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		key += "-items"
+	default:
+		return trans.T(key+"-number", fe.Param())
+	}
+	n, err := strconv.ParseFloat(fe.Param(), 64)
+	if err != nil {
+		return "", err
+	}
+	return trans.C(key, n, 0, fe.Param())
+}
@@ -0,0 +1,110 @@
+package helper
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterStructValidation(validateProductFilter, dto.ProductFilterParams{})
+	}
+}
+
+// validateProductFilter checks the sort column of a product listing and that
+// its ranges are not inverted. Prices that do not parse are left to the
+// usecase, which reports them.
+func validateProductFilter(sl validator.StructLevel) {
+	params := sl.Current().Interface().(dto.ProductFilterParams)
+	if params.SortBy != "" && !slices.Contains(dto.ProductSortByColumns, params.SortBy) {
+		sl.ReportError(params.SortBy, "sort_by", "SortBy", "oneof", strings.Join(dto.ProductSortByColumns, " "))
+	}
+	if params.StockMin != nil && params.StockMax != nil && *params.StockMin > *params.StockMax {
+		sl.ReportError(params.StockMin, "stock_min", "StockMin", "ltefield", "stock_max")
+	}
+	if params.PriceMin != nil && params.PriceMax != nil {
+		lo, okLo := new(big.Rat).SetString(*params.PriceMin)
+		hi, okHi := new(big.Rat).SetString(*params.PriceMax)
+		if okLo && okHi && lo.Cmp(hi) > 0 {
+			sl.ReportError(params.PriceMin, "price_min", "PriceMin", "ltefield", "price_max")
+		}
+	}
+}
+
+// ruleErrorsKey is the context key of the ruleErrors of a validation.
+type ruleErrorsKey struct{}
+
+// ruleErrors collects the errors of the lookups of the database rules, which
+// a validator.StructLevel cannot return.
+type ruleErrors struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (e *ruleErrors) add(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.err == nil {
+		e.err = err
+	}
+}
+
+// RegisterDatabaseRules registers the validation rules that look up stored
+// data with Gin's validator engine. Call it once at startup, before anything
+// is validated: the engine caches the rules of each type. The rules only
+// run under the domain.RequestValidator, which can report a failed lookup;
+// binding a request skips them.
+func RegisterDatabaseRules(repo domain.ValidationRepository) {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterStructValidationCtx(productRule(repo), domain.Product{})
+	}
+}
+
+// productRule checks that the category of a product exists and that no
+// other product of it has the same name. Both lookups run at once.
+func productRule(repo domain.ValidationRepository) validator.StructLevelFuncCtx {
+	return func(ctx context.Context, sl validator.StructLevel) {
+		errs, ok := ctx.Value(ruleErrorsKey{}).(*ruleErrors)
+		if !ok {
+			return
+		}
+		product := sl.Current().Interface().(domain.Product)
+		if product.CategoryID == 0 {
+			return
+		}
+
+		var categoryExists, nameTaken bool
+		var wg sync.WaitGroup
+		wg.Go(func() {
+			exists, err := repo.CategoryExists(ctx, product.CategoryID)
+			if err != nil {
+				errs.add(err)
+			}
+			categoryExists = exists
+		})
+		if product.Name != "" {
+			wg.Go(func() {
+				taken, err := repo.ProductNameTaken(ctx, product.CategoryID, product.Name, product.ID)
+				if err != nil {
+					errs.add(err)
+				}
+				nameTaken = taken
+			})
+		}
+		wg.Wait()
+
+		if !categoryExists {
+			sl.ReportError(product.CategoryID, "category_id", "CategoryID", "category_exists", "")
+		} else if nameTaken {
+			sl.ReportError(product.Name, "name", "Name", "unique_name", "")
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type categoryAttributeHandler struct {
+	attributeUsecase domain.CategoryAttributeUsecase
+}
+
+func NewCategoryAttributeHandler(r *gin.Engine, attributeUsecase domain.CategoryAttributeUsecase) {
+	handler := &categoryAttributeHandler{
+		attributeUsecase: attributeUsecase,
+	}
+
+	r.GET("/category/:id/attributes", handler.GetAttributes)
+	r.POST("/category/:id/attributes", handler.CreateAttribute)
+	r.PUT("/category/:id/attributes/:attribute_id", handler.EditAttribute)
+	r.DELETE("/category/:id/attributes/:attribute_id", handler.DeleteAttribute)
+}
+
+// GetAttributes lists the attributes that apply to the products of the
+// category, including those inherited from its ancestors.
+func (h *categoryAttributeHandler) GetAttributes(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	attributes, err := h.attributeUsecase.GetAttributes(c, categoryID)
+	if err != nil {
+		attributeErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get attributes success",
+		"data":    attributes,
+	})
+}
+
+func (h *categoryAttributeHandler) CreateAttribute(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.CategoryAttributeRequest
+	if !bindAttributeRequest(c, &req) {
+		return
+	}
+
+	attribute, err := h.attributeUsecase.CreateAttribute(c, categoryID, &req)
+	if err != nil {
+		attributeErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  http.StatusCreated,
+		"message": "Attribute created successfully",
+		"data":    attribute,
+	})
+}
+
+// EditAttribute replaces the options and required flag of an attribute
+// defined on the category itself.
+func (h *categoryAttributeHandler) EditAttribute(c *gin.Context) {
+	categoryID, id, ok := attributeIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCategoryAttributeRequest
+	if !bindAttributeRequest(c, &req) {
+		return
+	}
+
+	attribute, err := h.attributeUsecase.EditAttribute(c, categoryID, id, &req)
+	if err != nil {
+		attributeErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Attribute updated successfully",
+		"data":    attribute,
+	})
+}
+
+func (h *categoryAttributeHandler) DeleteAttribute(c *gin.Context) {
+	categoryID, id, ok := attributeIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.attributeUsecase.DeleteAttribute(c, categoryID, id); err != nil {
+		attributeErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Attribute deleted successfully",
+	})
+}
+
+func attributeIDs(c *gin.Context) (int, int, bool) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("attribute_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attribute ID"})
+		return 0, 0, false
+	}
+	return categoryID, id, true
+}
+
+func bindAttributeRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return false
+	}
+	return true
+}
+
+func attributeErrorResponse(c *gin.Context, err error) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Validation failed",
+			"errors":  validationErr.Fields,
+		})
+	case errors.Is(err, domain.ErrCategoryNotFound), errors.Is(err, domain.ErrAttributeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "the category already defines this attribute"})
+	case errors.Is(err, domain.ErrAttributeTypeConflict), errors.Is(err, domain.ErrAttributeInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
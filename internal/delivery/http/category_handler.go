@@ -23,14 +23,25 @@ func NewCategoryHandler(r *gin.Engine, categoryUsecase domain.CategoryUsecase) {
 	}
 
 	r.GET("/category", handler.GetAllCategories)
+	r.GET("/category/tree", handler.GetCategoryTree)
+	r.GET("/category/by-slug/:slug", handler.GetCategoryBySlug)
 	r.GET("/category/:id", handler.GetCategoryByID)
+	r.GET("/category/:id/tree", handler.GetCategorySubtree)
+	r.GET("/category/:id/breadcrumbs", handler.GetCategoryBreadcrumbs)
 	r.POST("/category", handler.CreateCategory)
 	r.PUT("/category/:id", handler.EditCategory)
+	r.PATCH("/category/:id", handler.PatchCategory)
+	r.POST("/category/:id/move", handler.MoveCategory)
 	r.DELETE("/category/:id", handler.DeleteCategory)
 }
 
 func (h *categoryHandler) GetAllCategories(c *gin.Context) {
-	categories, err := h.categoryUsecase.GetAllCategories(c.Request.Context())
+	proj, ok := bindProjection(c, dto.CategoryFields, nil)
+	if !ok {
+		return
+	}
+
+	categories, err := h.categoryUsecase.GetAllCategories(c.Request.Context(), proj)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  http.StatusInternalServerError,
@@ -39,10 +50,71 @@ func (h *categoryHandler) GetAllCategories(c *gin.Context) {
 		})
 		return
 	}
+	data, err := helper.Project(categories, proj, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"status":  http.StatusOK,
 		"message": "get categories success",
-		"data":    categories,
+		"data":    data,
+	})
+}
+
+// GetCategoryTree returns every category nested under its parent.
+func (h *categoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.categoryUsecase.GetCategoryTree(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  http.StatusInternalServerError,
+			"message": "get category tree failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get category tree success",
+		"data":    tree,
+	})
+}
+
+func (h *categoryHandler) GetCategorySubtree(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	tree, err := h.categoryUsecase.GetCategorySubtree(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get category subtree success",
+		"data":    tree,
+	})
+}
+
+func (h *categoryHandler) GetCategoryBreadcrumbs(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	breadcrumbs, err := h.categoryUsecase.GetCategoryBreadcrumbs(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get category breadcrumbs success",
+		"data":    breadcrumbs,
 	})
 }
 
@@ -54,31 +126,75 @@ func (h *categoryHandler) GetCategoryByID(c *gin.Context) {
 		return
 	}
 
-	category, err := h.categoryUsecase.GetCategoryByID(c.Request.Context(), id)
+	proj, ok := bindProjection(c, dto.CategoryFields, nil)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryUsecase.GetCategoryByID(c.Request.Context(), id, proj)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
+	data, err := helper.Project(category, proj, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
 		"status":  http.StatusOK,
 		"message": "get category success",
-		"data":    category,
+		"data":    data,
 	})
 }
 
+// GetCategoryBySlug looks a category up by slug. A slug retired by a rename
+// still resolves, and the response then points at the canonical URL so the
+// client can redirect.
+func (h *categoryHandler) GetCategoryBySlug(c *gin.Context) {
+	proj, ok := bindProjection(c, dto.CategoryFields, nil)
+	if !ok {
+		return
+	}
+
+	slug := c.Param("slug")
+	category, err := h.categoryUsecase.GetCategoryBySlug(c.Request.Context(), slug, proj)
+	if err != nil {
+		if errors.Is(err, domain.ErrSlugNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := helper.Project(category, proj, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := gin.H{
+		"status":  http.StatusOK,
+		"message": "get category success",
+		"data":    data,
+	}
+	if category.Slug != slug {
+		canonical := "/category/by-slug/" + category.Slug
+		c.Header("Link", "<"+canonical+`>; rel="canonical"`)
+		resp["redirect_to"] = canonical
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *categoryHandler) CreateCategory(c *gin.Context) {
 	var req dto.CreateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		var ve validator.ValidationErrors
 		if errors.As(err, &ve) {
-			fieldErrors := make(map[string]string)
-			for _, fe := range ve {
-				fieldErrors[fe.Field()] = helper.MsgForTag(fe)
-			}
 			c.JSON(http.StatusBadRequest, gin.H{
 				"status":  http.StatusBadRequest,
 				"message": "Validation failed",
-				"errors":  fieldErrors,
+				"errors":  helper.FieldErrors(c, ve),
 			})
 			return
 		}
@@ -90,11 +206,21 @@ func (h *categoryHandler) CreateCategory(c *gin.Context) {
 	}
 
 	category := domain.Category{
-		Name:        req.Name,
-		Description: req.Description,
+		Name:             req.Name,
+		Description:      req.Description,
+		ParentID:         req.ParentID,
+		ReorderThreshold: req.ReorderThreshold,
 	}
 
 	if err := h.categoryUsecase.CreateCategory(c, &category); err != nil {
+		if errors.Is(err, domain.ErrParentCategoryNotFound) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -105,6 +231,8 @@ func (h *categoryHandler) CreateCategory(c *gin.Context) {
 	})
 }
 
+// EditCategory replaces the category; an omitted description is cleared.
+// Use PATCH for partial updates.
 func (h *categoryHandler) EditCategory(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -112,18 +240,14 @@ func (h *categoryHandler) EditCategory(c *gin.Context) {
 		return
 	}
 
-	var req dto.UpdateCategoryRequest
+	var req dto.ReplaceCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		var ve validator.ValidationErrors
 		if errors.As(err, &ve) {
-			fieldErrors := make(map[string]string)
-			for _, fe := range ve {
-				fieldErrors[fe.Field()] = helper.MsgForTag(fe)
-			}
 			c.JSON(http.StatusBadRequest, gin.H{
 				"status":  http.StatusBadRequest,
 				"message": "Validation failed",
-				"errors":  fieldErrors,
+				"errors":  helper.FieldErrors(c, ve),
 			})
 			return
 		}
@@ -133,8 +257,12 @@ func (h *categoryHandler) EditCategory(c *gin.Context) {
 		})
 		return
 	}
-	category, err := h.categoryUsecase.EditCategory(c, id, &req)
+	category, err := h.categoryUsecase.ReplaceCategory(c, id, &req)
 	if err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -145,6 +273,86 @@ func (h *categoryHandler) EditCategory(c *gin.Context) {
 	})
 }
 
+// PatchCategory applies a JSON Merge Patch or JSON Patch document to the
+// category.
+func (h *categoryHandler) PatchCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	p, err := readPatch(c)
+	if err != nil {
+		if !patchErrorResponse(c, err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	category, err := h.categoryUsecase.PatchCategory(c, id, p)
+	if err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if !patchErrorResponse(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "category updated successfully",
+		"data":    category,
+	})
+}
+
+// MoveCategory re-parents a category together with its subtree.
+func (h *categoryHandler) MoveCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	category, err := h.categoryUsecase.MoveCategory(c.Request.Context(), id, req.ParentID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrCategoryCycle), errors.Is(err, domain.ErrParentCategoryNotFound):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, domain.ErrAttributesMismatch):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "category moved successfully",
+		"data":    category,
+	})
+}
+
 func (h *categoryHandler) DeleteCategory(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -154,6 +362,10 @@ func (h *categoryHandler) DeleteCategory(c *gin.Context) {
 	}
 
 	if err := h.categoryUsecase.DeleteCategory(c.Request.Context(), id); err != nil {
+		if errors.Is(err, domain.ErrCategoryHasChildren) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"test-elabram/internal/domain"
+	"test-elabram/internal/locale"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ActorHeader names the user or service a request acts for. It is recorded
+// with audited changes such as stock movements.
+const ActorHeader = "X-Actor"
+
+// anonymousActor is recorded for requests without an ActorHeader.
+const anonymousActor = "anonymous"
+
+// ActorMiddleware stores the request's actor in the request context. Gin
+// must be configured with ContextWithFallback so usecases that receive the
+// *gin.Context can read it.
+func ActorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := strings.TrimSpace(c.GetHeader(ActorHeader))
+		if actor == "" {
+			actor = anonymousActor
+		}
+		if len(actor) > 255 {
+			actor = actor[:255]
+		}
+		c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
+
+// LocaleMiddleware stores the locale product and category content is shown
+// in in the request context. The lang query parameter picks it; without one
+// it is negotiated from Accept-Language, falling back to locale.Default. The
+// chosen locale is echoed in Content-Language.
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		l := locale.Negotiate(c.GetHeader("Accept-Language"))
+		if lang := c.Query("lang"); lang != "" {
+			var ok bool
+			if l, ok = locale.Match(lang); !ok {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error": "unsupported lang, use one of " + strings.Join(locale.Supported, ", "),
+				})
+				return
+			}
+		}
+		c.Header("Content-Language", l)
+		c.Request = c.Request.WithContext(domain.WithLocale(c.Request.Context(), l))
+		c.Next()
+	}
+}
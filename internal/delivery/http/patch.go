@@ -0,0 +1,68 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"test-elabram/internal/domain"
+	"test-elabram/internal/patch"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errUnsupportedPatchType = errors.New("unsupported patch content type")
+
+// readPatch decodes the request body according to its content type:
+// application/merge-patch+json or application/json-patch+json.
+func readPatch(c *gin.Context) (domain.Patch, error) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != patch.MergePatchContentType && mediaType != patch.JSONPatchContentType {
+		return nil, errUnsupportedPatchType
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, err
+	}
+	if mediaType == patch.MergePatchContentType {
+		return patch.MergePatch(body), nil
+	}
+	return patch.ParseJSONPatch(body)
+}
+
+// patchErrorResponse writes the response for an error from reading or
+// applying a patch and reports whether err was one of those errors.
+func patchErrorResponse(c *gin.Context, err error) bool {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.Is(err, errUnsupportedPatchType):
+		c.Header("Accept-Patch", patch.MergePatchContentType+", "+patch.JSONPatchContentType)
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"status":  http.StatusUnsupportedMediaType,
+			"message": "Use " + patch.MergePatchContentType + " or " + patch.JSONPatchContentType,
+		})
+	case errors.Is(err, domain.ErrPatchTestFailed):
+		c.JSON(http.StatusConflict, gin.H{
+			"status":  http.StatusConflict,
+			"message": "Patch test operation failed",
+			"error":   err.Error(),
+		})
+	case errors.Is(err, domain.ErrInvalidPatch):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"status":  http.StatusUnprocessableEntity,
+			"message": "Invalid patch",
+			"error":   err.Error(),
+		})
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Validation failed",
+			"errors":  validationErr.Fields,
+		})
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type priceChangeHandler struct {
+	priceChangeUsecase domain.PriceChangeUsecase
+}
+
+func NewPriceChangeHandler(r *gin.Engine, priceChangeUsecase domain.PriceChangeUsecase) {
+	handler := &priceChangeHandler{
+		priceChangeUsecase: priceChangeUsecase,
+	}
+
+	r.GET("/products/:id/price-history", handler.GetPriceHistory)
+	r.POST("/products/:id/price-changes", handler.SchedulePriceChange)
+	r.DELETE("/products/:id/price-changes/:change_id", handler.CancelPriceChange)
+}
+
+// GetPriceHistory returns the price changes of a product with their
+// effective windows, latest first, including scheduled ones.
+func (h *priceChangeHandler) GetPriceHistory(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var pq dto.PaginationQuery
+	if !bindQuery(c, &pq, "Invalid pagination params") {
+		return
+	}
+
+	result, err := h.priceChangeUsecase.GetPriceHistory(c, productID, pq)
+	if err != nil {
+		priceChangeErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":      http.StatusOK,
+		"message":     "get price history success",
+		"data":        result.Data,
+		"page":        result.Page,
+		"limit":       result.Limit,
+		"total_items": result.TotalItems,
+		"total_pages": result.TotalPages,
+	})
+}
+
+// SchedulePriceChange schedules a future price for a product.
+func (h *priceChangeHandler) SchedulePriceChange(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.PriceChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	change, err := h.priceChangeUsecase.SchedulePriceChange(c, productID, &req)
+	if err != nil {
+		priceChangeErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  http.StatusCreated,
+		"message": "price change scheduled successfully",
+		"data":    change,
+	})
+}
+
+// CancelPriceChange removes a scheduled price change before it takes effect.
+func (h *priceChangeHandler) CancelPriceChange(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	changeID, err := strconv.Atoi(c.Param("change_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := h.priceChangeUsecase.CancelPriceChange(c, productID, changeID); err != nil {
+		priceChangeErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "price change cancelled successfully",
+	})
+}
+
+func priceChangeErrorResponse(c *gin.Context, err error) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Validation failed",
+			"errors":  validationErr.Fields,
+		})
+	case errors.Is(err, domain.ErrCurrencyMismatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrPriceChangeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrPriceChangeApplied):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,285 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// PriceListHeader selects the price list product reads are priced in when
+// the price_list query parameter is not given.
+const PriceListHeader = "X-Price-List"
+
+// requestPriceList returns the code of the price list the request selects,
+// or "" for the products' own prices.
+func requestPriceList(c *gin.Context) string {
+	if code := strings.TrimSpace(c.Query("price_list")); code != "" {
+		return code
+	}
+	return strings.TrimSpace(c.GetHeader(PriceListHeader))
+}
+
+type priceListHandler struct {
+	priceListUsecase domain.PriceListUsecase
+}
+
+func NewPriceListHandler(r *gin.Engine, priceListUsecase domain.PriceListUsecase) {
+	handler := &priceListHandler{
+		priceListUsecase: priceListUsecase,
+	}
+
+	r.GET("/price-lists", handler.GetPriceLists)
+	r.GET("/price-lists/:id", handler.GetPriceList)
+	r.POST("/price-lists", handler.CreatePriceList)
+	r.DELETE("/price-lists/:id", handler.DeletePriceList)
+	r.GET("/price-lists/:id/prices", handler.GetPriceListPrices)
+	r.PUT("/price-lists/:id/prices/:product_id", handler.SetPriceListPrice)
+	r.DELETE("/price-lists/:id/prices/:product_id", handler.DeletePriceListPrice)
+	r.GET("/exchange-rates", handler.GetExchangeRates)
+	r.PUT("/exchange-rates/:base/:quote", handler.SetExchangeRate)
+	r.DELETE("/exchange-rates/:base/:quote", handler.DeleteExchangeRate)
+}
+
+func (h *priceListHandler) GetPriceLists(c *gin.Context) {
+	priceLists, err := h.priceListUsecase.GetAllPriceLists(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get price lists success",
+		"data":    priceLists,
+	})
+}
+
+func (h *priceListHandler) GetPriceList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	priceList, err := h.priceListUsecase.GetPriceListByID(c, id)
+	if err != nil {
+		priceListErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get price list success",
+		"data":    priceList,
+	})
+}
+
+func (h *priceListHandler) CreatePriceList(c *gin.Context) {
+	var req dto.PriceListRequest
+	if !bindPriceListRequest(c, &req) {
+		return
+	}
+
+	priceList, err := h.priceListUsecase.CreatePriceList(c, &req)
+	if err != nil {
+		priceListErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  http.StatusCreated,
+		"message": "price list created successfully",
+		"data":    priceList,
+	})
+}
+
+// DeletePriceList removes a price list with its price overrides.
+func (h *priceListHandler) DeletePriceList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := h.priceListUsecase.DeletePriceList(c, id); err != nil {
+		priceListErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "price list deleted successfully",
+	})
+}
+
+// GetPriceListPrices lists the price overrides of a price list.
+func (h *priceListHandler) GetPriceListPrices(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var pq dto.PaginationQuery
+	if !bindQuery(c, &pq, "Invalid pagination params") {
+		return
+	}
+
+	result, err := h.priceListUsecase.GetPriceListPrices(c, id, pq)
+	if err != nil {
+		priceListErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":      http.StatusOK,
+		"message":     "get price list prices success",
+		"data":        result.Data,
+		"page":        result.Page,
+		"limit":       result.Limit,
+		"total_items": result.TotalItems,
+		"total_pages": result.TotalPages,
+	})
+}
+
+// SetPriceListPrice creates or replaces the price override of a product.
+func (h *priceListHandler) SetPriceListPrice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	productID, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.PriceListPriceRequest
+	if !bindPriceListRequest(c, &req) {
+		return
+	}
+
+	item, err := h.priceListUsecase.SetPriceListPrice(c, id, productID, &req)
+	if err != nil {
+		priceListErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "price list price set successfully",
+		"data":    item,
+	})
+}
+
+// DeletePriceListPrice removes the override of a product, whose price in
+// the list is then converted from its own price.
+func (h *priceListHandler) DeletePriceListPrice(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+	productID, err := strconv.Atoi(c.Param("product_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := h.priceListUsecase.DeletePriceListPrice(c, id, productID); err != nil {
+		priceListErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "price list price deleted successfully",
+	})
+}
+
+func (h *priceListHandler) GetExchangeRates(c *gin.Context) {
+	rates, err := h.priceListUsecase.GetExchangeRates(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get exchange rates success",
+		"data":    rates,
+	})
+}
+
+// SetExchangeRate creates or replaces the rate converting the base currency
+// to the quote currency.
+func (h *priceListHandler) SetExchangeRate(c *gin.Context) {
+	var req dto.ExchangeRateRequest
+	if !bindPriceListRequest(c, &req) {
+		return
+	}
+
+	rate, err := h.priceListUsecase.SetExchangeRate(c, c.Param("base"), c.Param("quote"), &req)
+	if err != nil {
+		priceListErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "exchange rate set successfully",
+		"data":    rate,
+	})
+}
+
+func (h *priceListHandler) DeleteExchangeRate(c *gin.Context) {
+	if err := h.priceListUsecase.DeleteExchangeRate(c, c.Param("base"), c.Param("quote")); err != nil {
+		priceListErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "exchange rate deleted successfully",
+	})
+}
+
+func bindPriceListRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return false
+	}
+	return true
+}
+
+func priceListErrorResponse(c *gin.Context, err error) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Validation failed",
+			"errors":  validationErr.Fields,
+		})
+	case errors.Is(err, domain.ErrPriceListCurrency):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrPriceListNotFound), errors.Is(err, domain.ErrProductNotFound),
+		errors.Is(err, domain.ErrPriceOverrideNotFound), errors.Is(err, domain.ErrExchangeRateNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "price list code is already in use"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -2,12 +2,16 @@ package http
 
 import (
 	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"test-elabram/internal/delivery/helper"
 	"test-elabram/internal/domain"
 	"test-elabram/internal/dto"
+	"test-elabram/internal/export"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -23,46 +27,63 @@ func NewProductHandler(r *gin.Engine, productUsecase domain.ProductUsecase) {
 	}
 
 	r.GET("/products/report", handler.GetProductReport)
+	r.GET("/products/export", handler.ExportProducts)
+	r.GET("/products/suggest", handler.SuggestProducts)
 	r.GET("/products", handler.GetAllProducts)
+	r.GET("/products/by-slug/:slug", handler.GetProductBySlug)
 	r.GET("/products/:id", handler.GetProductByID)
 	r.POST("/products", handler.CreateProduct)
+	r.POST("/products/batch", handler.BatchProducts)
 	r.PUT("/products/:id", handler.EditProduct)
+	r.PATCH("/products/:id", handler.PatchProduct)
 	r.DELETE("/products/:id", handler.DeleteProduct)
 }
 
 func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 	var pq dto.PaginationQuery
-	if err := c.ShouldBindQuery(&pq); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  http.StatusBadRequest,
-			"message": "Invalid pagination params",
-		})
+	if !bindQuery(c, &pq, "Invalid pagination params") {
 		return
 	}
 
 	var filters dto.ProductFilterParams
-	if err := c.ShouldBindQuery(&filters); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"status":  http.StatusBadRequest,
-			"message": "Invalid filter params",
-		})
+	if !bindQuery(c, &filters, "Invalid filter params") {
+		return
+	}
+	filters.PriceList = requestPriceList(c)
+
+	var fq dto.ProductFacetQuery
+	if !bindQuery(c, &fq, "Invalid filter params") {
+		return
+	}
+
+	proj, ok := bindProjection(c, dto.ProductFields, dto.ProductIncludes)
+	if !ok {
 		return
 	}
 
-	result, err := h.productUsecase.GetAllProductsPaginated(c, filters, pq)
+	result, facets, err := h.productUsecase.GetAllProductsPaginated(c, filters, pq, fq, proj)
+	if err != nil {
+		productReadErrorResponse(c, err)
+		return
+	}
+	data, err := helper.Project(result.Data, proj, dto.ProductIncludes)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"status":      http.StatusOK,
 		"message":     "get products success",
-		"data":        result.Data,
+		"data":        data,
 		"page":        result.Page,
 		"limit":       result.Limit,
 		"total_items": result.TotalItems,
 		"total_pages": result.TotalPages,
-	})
+	}
+	if facets != nil {
+		resp["facets"] = facets
+	}
+	c.JSON(http.StatusOK, resp)
 }
 
 func (h *ProductHandler) GetProductByID(c *gin.Context) {
@@ -73,7 +94,17 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 		return
 	}
 
-	product, err := h.productUsecase.GetProductByID(c, id)
+	proj, ok := bindProjection(c, dto.ProductFields, dto.ProductIncludes)
+	if !ok {
+		return
+	}
+
+	product, err := h.productUsecase.GetProductByID(c, id, requestPriceList(c), proj)
+	if err != nil {
+		productReadErrorResponse(c, err)
+		return
+	}
+	data, err := helper.Project(product, proj, dto.ProductIncludes)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -81,23 +112,57 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status":  http.StatusOK,
 		"message": "get product success",
-		"data":    product,
+		"data":    data,
 	})
 }
 
+// GetProductBySlug looks a product up by slug. A slug retired by a rename
+// still resolves, and the response then points at the canonical URL so the
+// client can redirect.
+func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
+	proj, ok := bindProjection(c, dto.ProductFields, dto.ProductIncludes)
+	if !ok {
+		return
+	}
+
+	slug := c.Param("slug")
+	product, err := h.productUsecase.GetProductBySlug(c, slug, proj)
+	if err != nil {
+		if errors.Is(err, domain.ErrSlugNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	data, err := helper.Project(product, proj, dto.ProductIncludes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	resp := gin.H{
+		"status":  http.StatusOK,
+		"message": "get product success",
+		"data":    data,
+	}
+	if product.Slug != slug {
+		canonical := "/products/by-slug/" + product.Slug
+		c.Header("Link", "<"+canonical+`>; rel="canonical"`)
+		resp["redirect_to"] = canonical
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	var req dto.CreateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		var ve validator.ValidationErrors
 		if errors.As(err, &ve) {
-			fieldErrors := make(map[string]string)
-			for _, fe := range ve {
-				fieldErrors[fe.Field()] = helper.MsgForTag(fe)
-			}
 			c.JSON(http.StatusBadRequest, gin.H{
 				"status":  http.StatusBadRequest,
 				"message": "Validation failed",
-				"errors":  fieldErrors,
+				"errors":  helper.FieldErrors(c, ve),
 			})
 			return
 		}
@@ -109,15 +174,31 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	}
 
 	product := domain.Product{
-		Name:          req.Name,
-		Description:   req.Description,
-		Price:         req.Price,
-		StockQuantity: req.StockQuantity,
-		IsActive:      req.IsActive,
-		CategoryID:    req.CategoryID,
+		Name:             req.Name,
+		Description:      req.Description,
+		Price:            req.Price.Money(),
+		StockQuantity:    req.StockQuantity,
+		IsActive:         req.IsActive,
+		CategoryID:       req.CategoryID,
+		ReorderThreshold: req.ReorderThreshold,
+		Tags:             domain.TagRefs(req.Tags),
+		Attributes:       req.Attributes,
 	}
 
 	if err := h.productUsecase.CreateProduct(c, &product); err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  validationErr.Fields,
+			})
+			return
+		}
+		if errors.Is(err, domain.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -128,6 +209,8 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	})
 }
 
+// EditProduct replaces every editable field of the product; use PATCH for
+// partial updates.
 func (h *ProductHandler) EditProduct(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -136,18 +219,14 @@ func (h *ProductHandler) EditProduct(c *gin.Context) {
 		return
 	}
 
-	var req dto.UpdateProductRequest
+	var req dto.ReplaceProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		var ve validator.ValidationErrors
 		if errors.As(err, &ve) {
-			fieldErrors := make(map[string]string)
-			for _, fe := range ve {
-				fieldErrors[fe.Field()] = helper.MsgForTag(fe)
-			}
 			c.JSON(http.StatusBadRequest, gin.H{
 				"status":  http.StatusBadRequest,
 				"message": "Validation failed",
-				"errors":  fieldErrors,
+				"errors":  helper.FieldErrors(c, ve),
 			})
 			return
 		}
@@ -158,8 +237,25 @@ func (h *ProductHandler) EditProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := h.productUsecase.EditProduct(c, id, &req)
+	product, err := h.productUsecase.ReplaceProduct(c, id, &req)
 	if err != nil {
+		var validationErr *domain.ValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  validationErr.Fields,
+			})
+			return
+		}
+		if errors.Is(err, domain.ErrConflict) || errors.Is(err, domain.ErrInsufficientStock) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrCurrencyMismatch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -170,6 +266,46 @@ func (h *ProductHandler) EditProduct(c *gin.Context) {
 	})
 }
 
+// PatchProduct applies a JSON Merge Patch or JSON Patch document to the
+// product.
+func (h *ProductHandler) PatchProduct(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	p, err := readPatch(c)
+	if err != nil {
+		if !patchErrorResponse(c, err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	product, err := h.productUsecase.PatchProduct(c, id, p)
+	if err != nil {
+		if errors.Is(err, domain.ErrConflict) || errors.Is(err, domain.ErrInsufficientStock) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, domain.ErrCurrencyMismatch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if !patchErrorResponse(c, err) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Product updated successfully",
+		"data":    product,
+	})
+}
+
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -188,6 +324,49 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	})
 }
 
+// BatchProducts applies a list of create, update and delete operations and
+// reports the outcome of each one. A fully successful batch returns 200, a
+// rejected atomic batch 422 and a partially applied best-effort batch 207.
+func (h *ProductHandler) BatchProducts(c *gin.Context) {
+	var req dto.BatchProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	result, err := h.productUsecase.BatchProducts(c, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, message := http.StatusOK, "batch applied successfully"
+	if result.Failed > 0 {
+		if result.Mode == dto.BatchModeAtomic {
+			status, message = http.StatusUnprocessableEntity, "batch rejected, no changes were applied"
+		} else {
+			status, message = http.StatusMultiStatus, "batch partially applied"
+		}
+	}
+	c.JSON(status, gin.H{
+		"status":  status,
+		"message": message,
+		"data":    result,
+	})
+}
+
 // GetProductReport returns a dashboard-style report of all products.
 func (h *ProductHandler) GetProductReport(c *gin.Context) {
 	report, err := h.productUsecase.GetProductReport(c)
@@ -201,3 +380,84 @@ func (h *ProductHandler) GetProductReport(c *gin.Context) {
 		"data":    report,
 	})
 }
+
+// SuggestProducts returns typo-tolerant autocomplete suggestions. It answers
+// 503 when the database cannot keep within the latency budget, which a client
+// typing ahead can ignore.
+func (h *ProductHandler) SuggestProducts(c *gin.Context) {
+	var query dto.ProductSuggestQuery
+	if !bindQuery(c, &query, "Invalid query params") {
+		return
+	}
+
+	suggestions, err := h.productUsecase.SuggestProducts(c, query)
+	if errors.Is(err, domain.ErrSuggestTimeout) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get product suggestions success",
+		"data":    suggestions,
+	})
+}
+
+// ExportProducts streams the filtered catalog as a CSV, NDJSON or XLSX
+// download. Once the first row is written the status can no longer change,
+// so a failure half-way is only logged and the download ends early.
+func (h *ProductHandler) ExportProducts(c *gin.Context) {
+	var eq dto.ExportQuery
+	if !bindQuery(c, &eq, "Invalid export format, use csv, ndjson or xlsx") {
+		return
+	}
+
+	var filters dto.ProductFilterParams
+	if !bindQuery(c, &filters, "Invalid filter params") {
+		return
+	}
+	if err := h.productUsecase.CheckExportFilters(c, &filters); err != nil {
+		productReadErrorResponse(c, err)
+		return
+	}
+
+	filename := fmt.Sprintf("catalog-%s.%s", time.Now().Format("20060102-150405"), eq.Format)
+	c.Header("Content-Type", export.ContentType(eq.Format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	w, err := export.NewWriter(eq.Format, c.Writer)
+	if err != nil {
+		log.Printf("[EXPORT] Failed to start export: %v", err)
+		return
+	}
+	err = h.productUsecase.ExportProducts(c, filters, w.WriteRow)
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		log.Printf("[EXPORT] Export aborted: %v", err)
+	}
+}
+
+// productReadErrorResponse writes the response for an error from reading
+// products. A price list that does not exist is a bad request parameter
+// here, not a missing resource.
+func productReadErrorResponse(c *gin.Context, err error) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Validation failed",
+			"errors":  validationErr.Fields,
+		})
+	case errors.Is(err, domain.ErrPriceListNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,273 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxImportSize = 32 << 20
+
+var importColumns = map[string]bool{
+	"id":             true,
+	"name":           true,
+	"description":    true,
+	"price":          true,
+	"currency":       true,
+	"stock_quantity": true,
+	"is_active":      true,
+	"category_id":    true,
+	"category":       true,
+}
+
+type productImportHandler struct {
+	importUsecase domain.ProductImportUsecase
+}
+
+func NewProductImportHandler(r *gin.Engine, importUsecase domain.ProductImportUsecase) {
+	handler := &productImportHandler{
+		importUsecase: importUsecase,
+	}
+
+	r.POST("/products/import", handler.ImportProducts)
+	r.GET("/products/import/:job_id", handler.GetImportJob)
+}
+
+// ImportProducts accepts a CSV or NDJSON file, either as the raw request body
+// or as the "file" field of a multipart form, and starts an import job.
+func (h *productImportHandler) ImportProducts(c *gin.Context) {
+	var opts dto.ImportOptions
+	if !bindQuery(c, &opts, "Invalid import params") {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	var body io.Reader = c.Request.Body
+	format := opts.Format
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	if mediaType == "multipart/form-data" {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Missing file field",
+			})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		defer f.Close()
+		body = f
+		if format == "" {
+			format = importFormatFromExt(fh.Filename)
+		}
+	} else if format == "" {
+		format = importFormatFromContentType(mediaType)
+	}
+
+	var rows []dto.ImportProductRow
+	var err error
+	switch format {
+	case "csv":
+		rows, err = parseCSVImport(body)
+	case "ndjson":
+		rows, err = parseNDJSONImport(body)
+	default:
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{
+			"status":  http.StatusUnsupportedMediaType,
+			"message": "Unsupported import format, use csv or ndjson",
+		})
+		return
+	}
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"status":  http.StatusRequestEntityTooLarge,
+				"message": fmt.Sprintf("Import file exceeds %d bytes", maxImportSize),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid import file",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	job, err := h.importUsecase.StartImport(c, rows, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Location", "/products/import/"+job.ID)
+	c.JSON(http.StatusAccepted, gin.H{
+		"status":  http.StatusAccepted,
+		"message": "product import started",
+		"data":    job,
+	})
+}
+
+func (h *productImportHandler) GetImportJob(c *gin.Context) {
+	job, err := h.importUsecase.GetImportJob(c, c.Param("job_id"))
+	if err != nil {
+		if errors.Is(err, domain.ErrImportJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get import job success",
+		"data":    job,
+	})
+}
+
+func importFormatFromExt(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return "csv"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	}
+	return ""
+}
+
+func importFormatFromContentType(mediaType string) string {
+	switch mediaType {
+	case "text/csv":
+		return "csv"
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return "ndjson"
+	}
+	return ""
+}
+
+// parseCSVImport reads a CSV file with a header row. Cells that cannot be
+// decoded are reported on the row instead of failing the whole file; the row
+// number is the line in the file.
+func parseCSVImport(r io.Reader) ([]dto.ImportProductRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	for i, col := range header {
+		col = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")))
+		if !importColumns[col] {
+			return nil, fmt.Errorf("unknown column %q", col)
+		}
+		header[i] = col
+	}
+
+	var rows []dto.ImportProductRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := dto.ImportProductRow{Row: line, ParseErrors: map[string]dto.ParseError{}}
+		for i, col := range header {
+			if i >= len(record) {
+				break
+			}
+			value := strings.TrimSpace(record[i])
+			if value == "" {
+				continue
+			}
+			switch col {
+			case "id":
+				if id, err := strconv.ParseUint(value, 10, 64); err == nil {
+					uid := uint(id)
+					row.ID = &uid
+				} else {
+					row.ParseErrors[col] = dto.ParseError{Key: "cell_uint"}
+				}
+			case "name":
+				row.Name = value
+			case "description":
+				row.Description = value
+			case "price":
+				row.Price = json.Number(value)
+			case "currency":
+				row.Currency = value
+			case "stock_quantity":
+				if n, err := strconv.Atoi(value); err == nil {
+					row.StockQuantity = &n
+				} else {
+					row.ParseErrors[col] = dto.ParseError{Key: "cell_int"}
+				}
+			case "is_active":
+				if row.IsActive, err = strconv.ParseBool(value); err != nil {
+					row.ParseErrors[col] = dto.ParseError{Key: "cell_bool"}
+				}
+			case "category_id":
+				if id, err := strconv.ParseUint(value, 10, 64); err == nil {
+					uid := uint(id)
+					row.CategoryID = &uid
+				} else {
+					row.ParseErrors[col] = dto.ParseError{Key: "cell_uint"}
+				}
+			case "category":
+				row.Category = value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseNDJSONImport reads one JSON object per line. Blank lines are skipped
+// and a line that is not a valid product object is reported on its row.
+// Unknown fields are ignored, so the rows of an NDJSON export, which also
+// carry the category name and timestamps, import as they are.
+func parseNDJSONImport(r io.Reader) ([]dto.ImportProductRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	var rows []dto.ImportProductRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		data := bytes.TrimSpace(scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		var row dto.ImportProductRow
+		if err := json.Unmarshal(data, &row); err != nil {
+			row = dto.ImportProductRow{ParseErrors: map[string]dto.ParseError{"row": {Key: "row_json", Params: []string{err.Error()}}}}
+		}
+		row.Row = line
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
@@ -0,0 +1,233 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/media"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxMediaRequestSize leaves room for the multipart framing around the file.
+const maxMediaRequestSize = media.MaxSize + 1<<20
+
+type productMediaHandler struct {
+	mediaUsecase domain.ProductMediaUsecase
+}
+
+func NewProductMediaHandler(r *gin.Engine, mediaUsecase domain.ProductMediaUsecase) {
+	handler := &productMediaHandler{
+		mediaUsecase: mediaUsecase,
+	}
+
+	r.GET("/products/:id/media", handler.GetMedia)
+	r.POST("/products/:id/media", handler.UploadMedia)
+	r.PUT("/products/:id/media/order", handler.ReorderMedia)
+	r.PUT("/products/:id/media/:media_id/primary", handler.SetPrimaryMedia)
+	r.DELETE("/products/:id/media/:media_id", handler.DeleteMedia)
+	r.GET("/media/*key", handler.ServeFile)
+}
+
+func (h *productMediaHandler) GetMedia(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	list, err := h.mediaUsecase.GetMedia(c, productID)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get media success",
+		"data":    list,
+	})
+}
+
+// UploadMedia accepts a JPEG, PNG or GIF image as the "file" field of a
+// multipart form. Its type is sniffed from its content.
+func (h *productMediaHandler) UploadMedia(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxMediaRequestSize)
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			mediaErrorResponse(c, domain.ErrMediaTooLarge)
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Missing file field",
+		})
+		return
+	}
+	var req dto.MediaUploadRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid form fields",
+		})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	defer f.Close()
+
+	item, err := h.mediaUsecase.UploadMedia(c, productID, f, req.IsPrimary)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  http.StatusCreated,
+		"message": "media uploaded successfully",
+		"data":    item,
+	})
+}
+
+// ReorderMedia sets the display order of every media of the product.
+func (h *productMediaHandler) ReorderMedia(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.MediaOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	list, err := h.mediaUsecase.ReorderMedia(c, productID, req.MediaIDs)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "media reordered successfully",
+		"data":    list,
+	})
+}
+
+func (h *productMediaHandler) SetPrimaryMedia(c *gin.Context) {
+	productID, id, ok := mediaIDs(c)
+	if !ok {
+		return
+	}
+
+	item, err := h.mediaUsecase.SetPrimaryMedia(c, productID, id)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "primary media updated successfully",
+		"data":    item,
+	})
+}
+
+func (h *productMediaHandler) DeleteMedia(c *gin.Context) {
+	productID, id, ok := mediaIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.mediaUsecase.DeleteMedia(c, productID, id); err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "media deleted successfully",
+	})
+}
+
+// ServeFile serves a stored media file. Keys are random and never reused, so
+// the files may be cached indefinitely.
+func (h *productMediaHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	f, err := h.mediaUsecase.OpenFile(c, key)
+	if err != nil {
+		mediaErrorResponse(c, err)
+		return
+	}
+	defer f.Close()
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.DataFromReader(http.StatusOK, -1, contentType, f, map[string]string{
+		"Cache-Control":          "public, max-age=31536000, immutable",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func mediaIDs(c *gin.Context) (int, int, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("media_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid media ID"})
+		return 0, 0, false
+	}
+	return productID, id, true
+}
+
+func mediaErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrMediaNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrMediaTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error": fmt.Sprintf("%s, the limit is %d bytes and %d pixels", err, media.MaxSize, media.MaxPixels),
+		})
+	case errors.Is(err, domain.ErrMediaUnsupported):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrMediaOrder):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,180 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type productVariantHandler struct {
+	variantUsecase domain.ProductVariantUsecase
+}
+
+func NewProductVariantHandler(r *gin.Engine, variantUsecase domain.ProductVariantUsecase) {
+	handler := &productVariantHandler{
+		variantUsecase: variantUsecase,
+	}
+
+	r.GET("/products/:id/variants", handler.GetVariants)
+	r.GET("/products/:id/variants/:variant_id", handler.GetVariant)
+	r.POST("/products/:id/variants", handler.CreateVariant)
+	r.PUT("/products/:id/variants/:variant_id", handler.ReplaceVariant)
+	r.DELETE("/products/:id/variants/:variant_id", handler.DeleteVariant)
+}
+
+func (h *productVariantHandler) GetVariants(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	variants, err := h.variantUsecase.GetVariants(c, productID)
+	if err != nil {
+		variantErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get variants success",
+		"data":    variants,
+	})
+}
+
+func (h *productVariantHandler) GetVariant(c *gin.Context) {
+	productID, id, ok := variantIDs(c)
+	if !ok {
+		return
+	}
+
+	variant, err := h.variantUsecase.GetVariant(c, productID, id)
+	if err != nil {
+		variantErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get variant success",
+		"data":    variant,
+	})
+}
+
+func (h *productVariantHandler) CreateVariant(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.VariantRequest
+	if !bindVariantRequest(c, &req) {
+		return
+	}
+
+	variant, err := h.variantUsecase.CreateVariant(c, productID, &req)
+	if err != nil {
+		variantErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  http.StatusCreated,
+		"message": "variant created successfully",
+		"data":    variant,
+	})
+}
+
+// ReplaceVariant replaces every field of the variant.
+func (h *productVariantHandler) ReplaceVariant(c *gin.Context) {
+	productID, id, ok := variantIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReplaceVariantRequest
+	if !bindVariantRequest(c, &req) {
+		return
+	}
+
+	variant, err := h.variantUsecase.ReplaceVariant(c, productID, id, &req)
+	if err != nil {
+		variantErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "variant updated successfully",
+		"data":    variant,
+	})
+}
+
+func (h *productVariantHandler) DeleteVariant(c *gin.Context) {
+	productID, id, ok := variantIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.variantUsecase.DeleteVariant(c, productID, id); err != nil {
+		variantErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "variant deleted successfully",
+	})
+}
+
+func variantIDs(c *gin.Context) (int, int, bool) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return 0, 0, false
+	}
+	id, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid variant ID"})
+		return 0, 0, false
+	}
+	return productID, id, true
+}
+
+func bindVariantRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return false
+	}
+	return true
+}
+
+func variantErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrVariantNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrCurrencyMismatch):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "SKU is already in use"})
+	case errors.Is(err, domain.ErrDuplicateVariantOptions), errors.Is(err, domain.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
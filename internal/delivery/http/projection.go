@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindProjection reads the fields and include query parameters of a read
+// endpoint. It answers 400 and returns false when they name fields or
+// relations the endpoint does not have.
+func bindProjection(c *gin.Context, fields []string, includes []string) (dto.Projection, bool) {
+	var fq dto.FieldsQuery
+	if err := c.ShouldBindQuery(&fq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid query params",
+		})
+		return dto.Projection{}, false
+	}
+	proj, paramErrors := fq.Projection(fields, includes)
+	if len(paramErrors) > 0 {
+		fieldErrors := make(map[string]string, len(paramErrors))
+		for param, err := range paramErrors {
+			fieldErrors[param] = helper.Message(c, err.Key, err.Args()...)
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Validation failed",
+			"errors":  fieldErrors,
+		})
+		return dto.Projection{}, false
+	}
+	return proj, true
+}
@@ -0,0 +1,35 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"test-elabram/internal/delivery/helper"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// bindQuery reads query parameters into obj. It answers 400 and returns
+// false when they do not parse, with message, or break obj's validation
+// rules, with the errors by parameter.
+func bindQuery(c *gin.Context, obj interface{}, message string) bool {
+	err := c.ShouldBindQuery(obj)
+	if err == nil {
+		return true
+	}
+	var ve validator.ValidationErrors
+	if errors.As(err, &ve) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Validation failed",
+			"errors":  helper.FieldErrors(c, ve),
+		})
+		return false
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"status":  http.StatusBadRequest,
+		"message": message,
+	})
+	return false
+}
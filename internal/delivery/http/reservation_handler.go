@@ -0,0 +1,139 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type reservationHandler struct {
+	reservationUsecase domain.ReservationUsecase
+}
+
+func NewReservationHandler(r *gin.Engine, reservationUsecase domain.ReservationUsecase) {
+	handler := &reservationHandler{
+		reservationUsecase: reservationUsecase,
+	}
+
+	r.POST("/reservations", handler.CreateReservation)
+	r.GET("/reservations/:id", handler.GetReservation)
+	r.POST("/reservations/:id/confirm", handler.ConfirmReservation)
+	r.POST("/reservations/:id/release", handler.ReleaseReservation)
+}
+
+func (h *reservationHandler) GetReservation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	reservation, err := h.reservationUsecase.GetReservation(c, id)
+	if err != nil {
+		reservationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get reservation success",
+		"data":    reservation,
+	})
+}
+
+// CreateReservation holds stock for a checkout until the reservation is
+// confirmed, released or expires.
+func (h *reservationHandler) CreateReservation(c *gin.Context) {
+	var req dto.ReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	reservation, err := h.reservationUsecase.CreateReservation(c, &req)
+	if err != nil {
+		reservationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  http.StatusCreated,
+		"message": "reservation created successfully",
+		"data":    reservation,
+	})
+}
+
+// ConfirmReservation turns the held stock into sales.
+func (h *reservationHandler) ConfirmReservation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.ConfirmReservationRequest
+	if !bindQuery(c, &req, "Invalid query params") {
+		return
+	}
+
+	reservation, err := h.reservationUsecase.ConfirmReservation(c, id, &req)
+	if err != nil {
+		reservationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "reservation confirmed successfully",
+		"data":    reservation,
+	})
+}
+
+// ReleaseReservation gives the held stock back.
+func (h *reservationHandler) ReleaseReservation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	reservation, err := h.reservationUsecase.ReleaseReservation(c, id)
+	if err != nil {
+		reservationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "reservation released successfully",
+		"data":    reservation,
+	})
+}
+
+func reservationErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrReservationNotFound), errors.Is(err, domain.ErrProductNotFound),
+		errors.Is(err, domain.ErrVariantNotFound), errors.Is(err, domain.ErrWarehouseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInsufficientStock), errors.Is(err, domain.ErrReservationExpired),
+		errors.Is(err, domain.ErrReservationClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
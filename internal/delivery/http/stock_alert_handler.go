@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+type stockAlertHandler struct {
+	alertUsecase domain.StockAlertUsecase
+}
+
+func NewStockAlertHandler(r *gin.Engine, alertUsecase domain.StockAlertUsecase) {
+	handler := &stockAlertHandler{
+		alertUsecase: alertUsecase,
+	}
+
+	r.GET("/products/low-stock", handler.GetLowStockProducts)
+}
+
+// GetLowStockProducts lists the products at or below their reorder
+// threshold, the most understocked first.
+func (h *stockAlertHandler) GetLowStockProducts(c *gin.Context) {
+	var pq dto.PaginationQuery
+	if !bindQuery(c, &pq, "Invalid pagination params") {
+		return
+	}
+
+	result, err := h.alertUsecase.GetLowStockProducts(c, pq)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":      http.StatusOK,
+		"message":     "get low-stock products success",
+		"data":        result.Data,
+		"page":        result.Page,
+		"limit":       result.Limit,
+		"total_items": result.TotalItems,
+		"total_pages": result.TotalPages,
+	})
+}
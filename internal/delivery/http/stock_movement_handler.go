@@ -0,0 +1,157 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type stockMovementHandler struct {
+	movementUsecase domain.StockMovementUsecase
+}
+
+func NewStockMovementHandler(r *gin.Engine, movementUsecase domain.StockMovementUsecase) {
+	handler := &stockMovementHandler{
+		movementUsecase: movementUsecase,
+	}
+
+	r.GET("/products/:id/stock-movements", handler.GetMovements)
+	r.POST("/products/:id/stock-movements", handler.RecordMovement)
+	r.POST("/stock-transfers", handler.TransferStock)
+}
+
+// GetMovements returns the stock ledger of a product, newest first.
+func (h *stockMovementHandler) GetMovements(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var pq dto.PaginationQuery
+	if !bindQuery(c, &pq, "Invalid pagination params") {
+		return
+	}
+	var query dto.StockMovementQuery
+	if !bindQuery(c, &query, "Invalid filter params") {
+		return
+	}
+
+	result, err := h.movementUsecase.GetMovements(c, productID, query, pq)
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":      http.StatusOK,
+		"message":     "get stock movements success",
+		"data":        result.Data,
+		"page":        result.Page,
+		"limit":       result.Limit,
+		"total_items": result.TotalItems,
+		"total_pages": result.TotalPages,
+	})
+}
+
+// RecordMovement changes the stock of a product or one of its variants in a
+// warehouse and returns the ledger entry with the new balance.
+func (h *stockMovementHandler) RecordMovement(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.StockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	movement, err := h.movementUsecase.RecordMovement(c, productID, &req)
+	if err != nil {
+		movementErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  http.StatusCreated,
+		"message": "stock movement recorded successfully",
+		"data":    movement,
+	})
+}
+
+// TransferStock moves stock between two warehouses and returns the outgoing
+// and incoming ledger entries.
+func (h *stockMovementHandler) TransferStock(c *gin.Context) {
+	var req dto.StockTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return
+	}
+
+	movements, err := h.movementUsecase.TransferStock(c, &req)
+	if err != nil {
+		movementErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  http.StatusCreated,
+		"message": "stock transferred successfully",
+		"data":    movements,
+	})
+}
+
+func movementErrorResponse(c *gin.Context, err error) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Validation failed",
+			"errors":  validationErr.Fields,
+		})
+	case errors.Is(err, domain.ErrSameWarehouseTarget):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrVariantNotFound), errors.Is(err, domain.ErrWarehouseNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
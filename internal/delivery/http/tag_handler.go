@@ -0,0 +1,141 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type tagHandler struct {
+	tagUsecase domain.TagUsecase
+}
+
+func NewTagHandler(r *gin.Engine, tagUsecase domain.TagUsecase) {
+	handler := &tagHandler{
+		tagUsecase: tagUsecase,
+	}
+
+	r.GET("/tags", handler.GetTags)
+	r.POST("/tags", handler.CreateTag)
+	r.PUT("/tags/:id", handler.ReplaceTag)
+	r.DELETE("/tags/:id", handler.DeleteTag)
+}
+
+func (h *tagHandler) GetTags(c *gin.Context) {
+	tags, err := h.tagUsecase.GetAllTags(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get tags success",
+		"data":    tags,
+	})
+}
+
+func (h *tagHandler) CreateTag(c *gin.Context) {
+	var req dto.TagRequest
+	if !bindTagRequest(c, &req) {
+		return
+	}
+
+	tag, err := h.tagUsecase.CreateTag(c, &req)
+	if err != nil {
+		tagErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  http.StatusCreated,
+		"message": "Tag created successfully",
+		"data":    tag,
+	})
+}
+
+// ReplaceTag renames the tag, which changes its slug too.
+func (h *tagHandler) ReplaceTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.TagRequest
+	if !bindTagRequest(c, &req) {
+		return
+	}
+
+	tag, err := h.tagUsecase.ReplaceTag(c, id, &req)
+	if err != nil {
+		tagErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Tag updated successfully",
+		"data":    tag,
+	})
+}
+
+// DeleteTag deletes the tag and takes it off its products.
+func (h *tagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := h.tagUsecase.DeleteTag(c, id); err != nil {
+		tagErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Tag deleted successfully",
+	})
+}
+
+func bindTagRequest(c *gin.Context, req *dto.TagRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return false
+	}
+	return true
+}
+
+func tagErrorResponse(c *gin.Context, err error) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Validation failed",
+			"errors":  validationErr.Fields,
+		})
+	case errors.Is(err, domain.ErrTagNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "a tag with this slug already exists"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
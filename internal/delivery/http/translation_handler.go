@@ -0,0 +1,231 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/locale"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type translationHandler struct {
+	translationUsecase domain.TranslationUsecase
+}
+
+func NewTranslationHandler(r *gin.Engine, translationUsecase domain.TranslationUsecase) {
+	handler := &translationHandler{
+		translationUsecase: translationUsecase,
+	}
+
+	r.GET("/products/translations/missing", handler.GetMissingProductTranslations)
+	r.GET("/products/:id/translations", handler.GetProductTranslations)
+	r.PUT("/products/:id/translations/:locale", handler.SaveProductTranslation)
+	r.DELETE("/products/:id/translations/:locale", handler.DeleteProductTranslation)
+	r.GET("/category/translations/missing", handler.GetMissingCategoryTranslations)
+	r.GET("/category/:id/translations", handler.GetCategoryTranslations)
+	r.PUT("/category/:id/translations/:locale", handler.SaveCategoryTranslation)
+	r.DELETE("/category/:id/translations/:locale", handler.DeleteCategoryTranslation)
+}
+
+func (h *translationHandler) GetProductTranslations(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	translations, err := h.translationUsecase.GetProductTranslations(c, id)
+	if err != nil {
+		translationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get product translations success",
+		"data":    translations,
+	})
+}
+
+// SaveProductTranslation creates or replaces the product's translation into
+// the locale of the path.
+func (h *translationHandler) SaveProductTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.TranslationRequest
+	if !bindTranslationRequest(c, &req) {
+		return
+	}
+
+	translation, err := h.translationUsecase.SaveProductTranslation(c, id, c.Param("locale"), &req)
+	if err != nil {
+		translationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Product translation saved successfully",
+		"data":    translation,
+	})
+}
+
+func (h *translationHandler) DeleteProductTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := h.translationUsecase.DeleteProductTranslation(c, id, c.Param("locale")); err != nil {
+		translationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Product translation deleted successfully",
+	})
+}
+
+// GetMissingProductTranslations lists the products lacking a translation,
+// once per locale they lack.
+func (h *translationHandler) GetMissingProductTranslations(c *gin.Context) {
+	h.getMissing(c, h.translationUsecase.GetMissingProductTranslations)
+}
+
+func (h *translationHandler) GetCategoryTranslations(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	translations, err := h.translationUsecase.GetCategoryTranslations(c, id)
+	if err != nil {
+		translationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get category translations success",
+		"data":    translations,
+	})
+}
+
+// SaveCategoryTranslation creates or replaces the category's translation
+// into the locale of the path.
+func (h *translationHandler) SaveCategoryTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.TranslationRequest
+	if !bindTranslationRequest(c, &req) {
+		return
+	}
+
+	translation, err := h.translationUsecase.SaveCategoryTranslation(c, id, c.Param("locale"), &req)
+	if err != nil {
+		translationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Category translation saved successfully",
+		"data":    translation,
+	})
+}
+
+func (h *translationHandler) DeleteCategoryTranslation(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := h.translationUsecase.DeleteCategoryTranslation(c, id, c.Param("locale")); err != nil {
+		translationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Category translation deleted successfully",
+	})
+}
+
+// GetMissingCategoryTranslations lists the categories lacking a
+// translation, once per locale they lack.
+func (h *translationHandler) GetMissingCategoryTranslations(c *gin.Context) {
+	h.getMissing(c, h.translationUsecase.GetMissingCategoryTranslations)
+}
+
+func (h *translationHandler) getMissing(c *gin.Context, list func(context.Context, dto.MissingTranslationQuery, dto.PaginationQuery) (*dto.PaginatedResponse, error)) {
+	var query dto.MissingTranslationQuery
+	var pq dto.PaginationQuery
+	if !bindQuery(c, &query, "Invalid query params") {
+		return
+	}
+	if !bindQuery(c, &pq, "Invalid pagination params") {
+		return
+	}
+
+	result, err := list(c, query, pq)
+	if err != nil {
+		translationErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":      http.StatusOK,
+		"message":     "get missing translations success",
+		"data":        result.Data,
+		"page":        result.Page,
+		"limit":       result.Limit,
+		"total_items": result.TotalItems,
+		"total_pages": result.TotalPages,
+	})
+}
+
+func bindTranslationRequest(c *gin.Context, req *dto.TranslationRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return false
+	}
+	return true
+}
+
+func translationErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrProductNotFound), errors.Is(err, domain.ErrCategoryNotFound), errors.Is(err, domain.ErrTranslationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrUnsupportedLocale):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error() + ", use one of " + strings.Join(locale.Translations(), ", "),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"test-elabram/internal/delivery/helper"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type warehouseHandler struct {
+	warehouseUsecase domain.WarehouseUsecase
+}
+
+func NewWarehouseHandler(r *gin.Engine, warehouseUsecase domain.WarehouseUsecase) {
+	handler := &warehouseHandler{
+		warehouseUsecase: warehouseUsecase,
+	}
+
+	r.GET("/warehouses", handler.GetWarehouses)
+	r.GET("/warehouses/:id", handler.GetWarehouse)
+	r.GET("/warehouses/:id/stock", handler.GetWarehouseStock)
+	r.POST("/warehouses", handler.CreateWarehouse)
+	r.PUT("/warehouses/:id", handler.ReplaceWarehouse)
+	r.DELETE("/warehouses/:id", handler.DeleteWarehouse)
+	r.GET("/products/:id/stock-levels", handler.GetProductStockLevels)
+}
+
+func (h *warehouseHandler) GetWarehouses(c *gin.Context) {
+	warehouses, err := h.warehouseUsecase.GetAllWarehouses(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get warehouses success",
+		"data":    warehouses,
+	})
+}
+
+func (h *warehouseHandler) GetWarehouse(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	warehouse, err := h.warehouseUsecase.GetWarehouseByID(c, id)
+	if err != nil {
+		warehouseErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get warehouse success",
+		"data":    warehouse,
+	})
+}
+
+// GetWarehouseStock lists the products and variants held in a warehouse.
+func (h *warehouseHandler) GetWarehouseStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	levels, err := h.warehouseUsecase.GetWarehouseStock(c, id)
+	if err != nil {
+		warehouseErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get warehouse stock success",
+		"data":    levels,
+	})
+}
+
+func (h *warehouseHandler) CreateWarehouse(c *gin.Context) {
+	var req dto.WarehouseRequest
+	if !bindWarehouseRequest(c, &req) {
+		return
+	}
+
+	warehouse, err := h.warehouseUsecase.CreateWarehouse(c, &req)
+	if err != nil {
+		warehouseErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  http.StatusCreated,
+		"message": "Warehouse created successfully",
+		"data":    warehouse,
+	})
+}
+
+func (h *warehouseHandler) ReplaceWarehouse(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req dto.WarehouseRequest
+	if !bindWarehouseRequest(c, &req) {
+		return
+	}
+
+	warehouse, err := h.warehouseUsecase.ReplaceWarehouse(c, id, &req)
+	if err != nil {
+		warehouseErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Warehouse updated successfully",
+		"data":    warehouse,
+	})
+}
+
+func (h *warehouseHandler) DeleteWarehouse(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	if err := h.warehouseUsecase.DeleteWarehouse(c, id); err != nil {
+		warehouseErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "Warehouse deleted successfully",
+	})
+}
+
+// GetProductStockLevels breaks the stock of a product and its variants down
+// by warehouse.
+func (h *warehouseHandler) GetProductStockLevels(c *gin.Context) {
+	productID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	levels, err := h.warehouseUsecase.GetProductStockLevels(c, productID)
+	if err != nil {
+		warehouseErrorResponse(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusOK,
+		"message": "get stock levels success",
+		"data":    levels,
+	})
+}
+
+func bindWarehouseRequest(c *gin.Context, req *dto.WarehouseRequest) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		var ve validator.ValidationErrors
+		if errors.As(err, &ve) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  http.StatusBadRequest,
+				"message": "Validation failed",
+				"errors":  helper.FieldErrors(c, ve),
+			})
+			return false
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  http.StatusBadRequest,
+			"message": "Invalid request body",
+		})
+		return false
+	}
+	return true
+}
+
+func warehouseErrorResponse(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrWarehouseNotFound), errors.Is(err, domain.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrWarehouseCodeTaken), errors.Is(err, domain.ErrDefaultChanged),
+		errors.Is(err, domain.ErrWarehouseNotEmpty), errors.Is(err, domain.ErrDefaultWarehouse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
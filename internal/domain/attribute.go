@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"test-elabram/internal/dto"
+	"time"
+)
+
+var (
+	ErrAttributeNotFound     = errors.New("attribute not found")
+	ErrAttributeTypeConflict = errors.New("an attribute with this name already exists with another type")
+	ErrAttributeInUse        = errors.New("attribute is set on products of the category")
+	ErrAttributesMismatch    = errors.New("products of the category have attribute values the attributes under the new parent do not allow")
+)
+
+// The types of attribute values. Numbers may have decimals; an enum is a
+// string from the attribute's options.
+const (
+	AttributeString  = "string"
+	AttributeNumber  = "number"
+	AttributeBoolean = "boolean"
+	AttributeEnum    = "enum"
+)
+
+// AttributeOptions are the allowed values of an enum attribute, stored as
+// JSONB.
+type AttributeOptions []string
+
+func (o AttributeOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(o)
+	return string(data), err
+}
+
+func (o *AttributeOptions) Scan(value interface{}) error {
+	data, err := jsonbBytes(value)
+	if err != nil || data == nil {
+		*o = AttributeOptions{}
+		return err
+	}
+	return json.Unmarshal(data, o)
+}
+
+// CategoryAttribute defines an attribute of the products of a category and
+// its descendants, e.g. screen_size on Laptops. A descendant may define the
+// same name again, e.g. to require it, and the nearest definition wins. A
+// name has one type across the catalog, so listings can filter on it
+// whatever the category.
+type CategoryAttribute struct {
+	ID         uint             `json:"id" gorm:"primarykey"`
+	CategoryID uint             `json:"category_id" gorm:"not null;uniqueIndex:idx_category_attributes_name,priority:1"`
+	Category   *Category        `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Name       string           `json:"name" gorm:"not null;uniqueIndex:idx_category_attributes_name,priority:2;index:idx_category_attributes_lookup"`
+	Type       string           `json:"type" gorm:"not null;check:type IN ('string', 'number', 'boolean', 'enum')"`
+	Options    AttributeOptions `json:"options" gorm:"type:jsonb;not null;default:'[]'"`
+	Required   bool             `json:"required" gorm:"not null;default:false"`
+	CreatedAt  time.Time        `json:"created_at"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// AttributeError is a problem with an attribute value. Key is the
+// validation message that describes it, which takes Params.
+type AttributeError struct {
+	Key    string
+	Params []string
+}
+
+// CheckAttributes checks attribute values against the attributes that
+// apply to a product, schema, by attribute name: required attributes must
+// have a value, and each value must be of an attribute of the schema and of
+// its type.
+func CheckAttributes(schema []CategoryAttribute, values ProductAttributes) map[string]AttributeError {
+	problems := map[string]AttributeError{}
+	defined := make(map[string]CategoryAttribute, len(schema))
+	for _, attribute := range schema {
+		defined[attribute.Name] = attribute
+		if _, ok := values[attribute.Name]; attribute.Required && !ok {
+			problems[attribute.Name] = AttributeError{Key: "required"}
+		}
+	}
+	for name, value := range values {
+		attribute, ok := defined[name]
+		if !ok {
+			problems[name] = AttributeError{Key: "attribute_unknown"}
+			continue
+		}
+		if problem, ok := attribute.check(value); !ok {
+			problems[name] = problem
+		}
+	}
+	return problems
+}
+
+// check reports whether value is of the attribute's type, and the problem
+// when it is not.
+func (a CategoryAttribute) check(value interface{}) (AttributeError, bool) {
+	var ok bool
+	switch a.Type {
+	case AttributeString:
+		_, ok = value.(string)
+	case AttributeNumber:
+		_, ok = value.(float64)
+	case AttributeBoolean:
+		_, ok = value.(bool)
+	case AttributeEnum:
+		s, isString := value.(string)
+		if !isString || !slices.Contains(a.Options, s) {
+			return AttributeError{Key: "oneof", Params: []string{strings.Join(a.Options, ", ")}}, false
+		}
+		return AttributeError{}, true
+	}
+	return AttributeError{Key: "attribute_" + a.Type}, ok
+}
+
+// ProductAttributes are the attribute values of a product by name, as
+// decoded from JSON: strings, float64 numbers and booleans. They are stored
+// as JSONB.
+type ProductAttributes map[string]interface{}
+
+func (a ProductAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(a)
+	return string(data), err
+}
+
+func (a *ProductAttributes) Scan(value interface{}) error {
+	data, err := jsonbBytes(value)
+	if err != nil || data == nil {
+		*a = ProductAttributes{}
+		return err
+	}
+	return json.Unmarshal(data, a)
+}
+
+func jsonbBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	case nil:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("cannot scan %T as JSONB", value)
+	}
+}
+
+type CategoryAttributeRepository interface {
+	// GetEffective returns the attributes that apply to the products of the
+	// category: its own and those of its ancestors, nearest first.
+	GetEffective(ctx context.Context, categoryID uint) ([]CategoryAttribute, error)
+	GetByID(ctx context.Context, categoryID int, id int) (*CategoryAttribute, error)
+	// Types returns the type of every attribute name.
+	Types(ctx context.Context) (map[string]string, error)
+	Create(ctx context.Context, attribute *CategoryAttribute) error
+	Edit(ctx context.Context, attribute *CategoryAttribute) error
+	Delete(ctx context.Context, categoryID int, id int) error
+}
+
+type CategoryAttributeUsecase interface {
+	GetAttributes(ctx context.Context, categoryID int) ([]CategoryAttribute, error)
+	CreateAttribute(ctx context.Context, categoryID int, req *dto.CategoryAttributeRequest) (*CategoryAttribute, error)
+	EditAttribute(ctx context.Context, categoryID int, id int, req *dto.UpdateCategoryAttributeRequest) (*CategoryAttribute, error)
+	DeleteAttribute(ctx context.Context, categoryID int, id int) error
+}
@@ -2,30 +2,57 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"test-elabram/internal/dto"
 	"time"
 )
 
+var (
+	ErrCategoryNotFound       = errors.New("category not found")
+	ErrParentCategoryNotFound = errors.New("parent category not found")
+	ErrCategoryCycle          = errors.New("a category cannot be moved under itself or its descendants")
+	ErrCategoryHasChildren    = errors.New("category has child categories")
+)
+
+// Category is a node of the category tree. Path is the materialized path of
+// ancestor IDs including the category itself, e.g. "/1/4/9/".
+// ReorderThreshold applies to the products of the category and its
+// descendants that set none themselves, unless a nearer category sets one.
 type Category struct {
-	ID          uint      `json:"id" gorm:"primarykey"`
-	Name        string    `json:"name" gorm:"not null"`
-	Description string    `json:"description" gorm:"not null"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID               uint      `json:"id" gorm:"primarykey"`
+	Name             string    `json:"name" gorm:"not null;index:idx_categories_name_trgm,type:gin,expression:name gin_trgm_ops"`
+	Slug             string    `json:"slug" gorm:"not null;uniqueIndex"`
+	Description      string    `json:"description" gorm:"not null"`
+	ParentID         *uint     `json:"parent_id" gorm:"index"`
+	Path             string    `json:"path" gorm:"not null;default:'';index"`
+	ReorderThreshold *int      `json:"reorder_threshold" gorm:"check:reorder_threshold >= 0"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 type CategoryRepository interface {
-	GetAll(ctx context.Context) ([]Category, error)
-	GetByID(ctx context.Context, id int) (*Category, error)
+	GetAll(ctx context.Context, columns ...string) ([]Category, error)
+	GetByID(ctx context.Context, id int, columns ...string) (*Category, error)
+	GetBySlug(ctx context.Context, slug string, columns ...string) (*Category, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]Category, error)
+	GetSubtree(ctx context.Context, path string) ([]Category, error)
+	HasChildren(ctx context.Context, id int) (bool, error)
 	Create(ctx context.Context, category *Category) error
 	Edit(ctx context.Context, category *Category) error
+	Move(ctx context.Context, id int, parentID *uint) (*Category, error)
 	Delete(ctx context.Context, id int) error
 }
 
 type CategoryUsecase interface {
-	GetAllCategories(ctx context.Context) ([]Category, error)
-	GetCategoryByID(ctx context.Context, id int) (*Category, error)
+	GetAllCategories(ctx context.Context, proj dto.Projection) ([]Category, error)
+	GetCategoryByID(ctx context.Context, id int, proj dto.Projection) (*Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string, proj dto.Projection) (*Category, error)
+	GetCategoryTree(ctx context.Context) ([]*dto.CategoryTreeNode, error)
+	GetCategorySubtree(ctx context.Context, id int) (*dto.CategoryTreeNode, error)
+	GetCategoryBreadcrumbs(ctx context.Context, id int) ([]Category, error)
 	CreateCategory(ctx context.Context, category *Category) error
-	EditCategory(ctx context.Context, id int, category *dto.UpdateCategoryRequest) (*Category, error)
+	ReplaceCategory(ctx context.Context, id int, req *dto.ReplaceCategoryRequest) (*Category, error)
+	PatchCategory(ctx context.Context, id int, patch Patch) (*Category, error)
+	MoveCategory(ctx context.Context, id int, parentID *uint) (*Category, error)
 	DeleteCategory(ctx context.Context, id int) error
 }
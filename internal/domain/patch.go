@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrInvalidPatch    = errors.New("invalid patch")
+	ErrPatchTestFailed = errors.New("patch test operation failed")
+)
+
+// Patch transforms the JSON representation of an entity, for example a JSON
+// Merge Patch (RFC 7386) or a JSON Patch (RFC 6902) document.
+type Patch interface {
+	Apply(doc []byte) ([]byte, error)
+}
+
+// ValidationError reports request fields that failed validation after the
+// request reached the usecase, e.g. on an entity produced by a patch.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return "validation failed: " + strings.Join(fields, ", ")
+}
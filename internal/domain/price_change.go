@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/money"
+	"time"
+)
+
+var (
+	ErrPriceChangeNotFound = errors.New("price change not found")
+	ErrPriceChangeApplied  = errors.New("price change already applied")
+)
+
+// PriceChange is an entry of a product's price history. A change made by
+// editing the product is applied at once; a scheduled one has an
+// EffectiveFrom in the future and no AppliedAt until the scheduler writes
+// its price to the product. EffectiveTo is the start of the next change, nil
+// for the latest one; it is computed by queries that select it and never
+// written. The price check is declared on ProductID, as GORM copies the tags
+// of an embedded field to each of its columns.
+type PriceChange struct {
+	ID            uint        `json:"id" gorm:"primarykey"`
+	ProductID     uint        `json:"product_id" gorm:"not null;index:idx_price_changes_product_effective_from,priority:1;check:chk_price_changes_price,price > 0"`
+	Product       *Product    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Price         money.Money `json:"price" gorm:"embedded"`
+	EffectiveFrom time.Time   `json:"effective_from" gorm:"not null;index:idx_price_changes_product_effective_from,priority:2;index:idx_price_changes_pending,where:applied_at IS NULL"`
+	EffectiveTo   *time.Time  `json:"effective_to" gorm:"->;-:migration"`
+	AppliedAt     *time.Time  `json:"applied_at"`
+	Reason        string      `json:"reason" gorm:"not null"`
+	Actor         string      `json:"actor" gorm:"not null"`
+	CreatedAt     time.Time   `json:"created_at"`
+}
+
+type PriceChangeRepository interface {
+	GetByProductID(ctx context.Context, productID int, pq dto.PaginationQuery) ([]PriceChange, int64, error)
+	Schedule(ctx context.Context, change *PriceChange) error
+	Cancel(ctx context.Context, productID int, changeID int) error
+	ApplyDue(ctx context.Context, now time.Time) ([]uint, error)
+	NextScheduled(ctx context.Context) (*time.Time, error)
+}
+
+type PriceChangeUsecase interface {
+	GetPriceHistory(ctx context.Context, productID int, pq dto.PaginationQuery) (*dto.PaginatedResponse, error)
+	SchedulePriceChange(ctx context.Context, productID int, req *dto.PriceChangeRequest) (*PriceChange, error)
+	CancelPriceChange(ctx context.Context, productID int, changeID int) error
+	ApplyDuePriceChanges(ctx context.Context) (int, error)
+	RunScheduler(ctx context.Context, interval time.Duration)
+}
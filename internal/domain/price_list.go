@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/money"
+	"time"
+)
+
+var (
+	ErrPriceListNotFound     = errors.New("price list not found")
+	ErrPriceListCurrency     = errors.New("price must be in the price list's currency")
+	ErrPriceOverrideNotFound = errors.New("product has no price override in the price list")
+	ErrExchangeRateNotFound  = errors.New("exchange rate not found")
+)
+
+// PriceList prices the catalog in one currency for a storefront or customer
+// group, e.g. retail-IDR or wholesale-USD. The price of a product in a list
+// is its override there, or else its effective price converted to the list's
+// currency with the exchange rate table.
+type PriceList struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Code      string    `json:"code" gorm:"not null;uniqueIndex"`
+	Name      string    `json:"name" gorm:"not null"`
+	Currency  string    `json:"currency" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PriceListItem overrides the price of a product in a price list. The price
+// check is declared on ProductID, as GORM copies the tags of an embedded
+// field to each of its columns.
+type PriceListItem struct {
+	ID          uint        `json:"id" gorm:"primarykey"`
+	PriceListID uint        `json:"price_list_id" gorm:"not null;uniqueIndex:idx_price_list_items_list_product,priority:1"`
+	PriceList   *PriceList  `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	ProductID   uint        `json:"product_id" gorm:"not null;uniqueIndex:idx_price_list_items_list_product,priority:2;index;check:chk_price_list_items_price,price > 0"`
+	Product     *Product    `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Price       money.Money `json:"price" gorm:"embedded"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// ExchangeRate converts prices from BaseCurrency to QuoteCurrency: one unit
+// of the base currency is worth Rate units of the quote currency. Rate is an
+// exact decimal, kept as a string so it is never rounded through a float.
+type ExchangeRate struct {
+	ID            uint      `json:"id" gorm:"primarykey"`
+	BaseCurrency  string    `json:"base_currency" gorm:"not null;uniqueIndex:idx_exchange_rates_pair,priority:1"`
+	QuoteCurrency string    `json:"quote_currency" gorm:"not null;uniqueIndex:idx_exchange_rates_pair,priority:2"`
+	Rate          string    `json:"rate" gorm:"type:numeric(20,10);not null;check:rate > 0"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type PriceListRepository interface {
+	GetAll(ctx context.Context) ([]PriceList, error)
+	GetByID(ctx context.Context, id int) (*PriceList, error)
+	GetByCode(ctx context.Context, code string) (*PriceList, error)
+	Create(ctx context.Context, priceList *PriceList) error
+	Delete(ctx context.Context, id int) error
+	GetPrices(ctx context.Context, priceListID int, pq dto.PaginationQuery) ([]PriceListItem, int64, error)
+	SetPrice(ctx context.Context, item *PriceListItem) error
+	DeletePrice(ctx context.Context, priceListID int, productID int) error
+	GetExchangeRates(ctx context.Context) ([]ExchangeRate, error)
+	SetExchangeRate(ctx context.Context, rate *ExchangeRate) error
+	DeleteExchangeRate(ctx context.Context, base string, quote string) error
+}
+
+type PriceListUsecase interface {
+	GetAllPriceLists(ctx context.Context) ([]PriceList, error)
+	GetPriceListByID(ctx context.Context, id int) (*PriceList, error)
+	CreatePriceList(ctx context.Context, req *dto.PriceListRequest) (*PriceList, error)
+	DeletePriceList(ctx context.Context, id int) error
+	GetPriceListPrices(ctx context.Context, id int, pq dto.PaginationQuery) (*dto.PaginatedResponse, error)
+	SetPriceListPrice(ctx context.Context, id int, productID int, req *dto.PriceListPriceRequest) (*PriceListItem, error)
+	DeletePriceListPrice(ctx context.Context, id int, productID int) error
+	GetExchangeRates(ctx context.Context) ([]ExchangeRate, error)
+	SetExchangeRate(ctx context.Context, base string, quote string, req *dto.ExchangeRateRequest) (*ExchangeRate, error)
+	DeleteExchangeRate(ctx context.Context, base string, quote string) error
+}
@@ -2,39 +2,105 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"test-elabram/internal/dto"
+	"test-elabram/internal/money"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProductNotFound  = errors.New("product not found")
+	ErrCurrencyMismatch = errors.New("currency must match the product's currency")
+	ErrSuggestTimeout   = errors.New("suggestions took too long")
+	ErrProductNameTaken = errors.New("another product of the category has this name")
 )
 
+// Product is a catalog item. AvailableQuantity is its own stock minus active
+// reservation holds and EffectivePrice the price of its latest price change
+// in effect; both are computed by queries that select them and never
+// written. Prices are in the product's currency, which is fixed when it is
+// created and shared by its variants and price history. ListPrice is only
+// set on reads in a price list that has a price for the product.
+// ReorderThreshold overrides the threshold of its category.
+//
+// SearchVector is the full-text index of the name and description, generated
+// by Postgres in both the Indonesian and the English configuration, as the
+// catalog mixes both languages. The application never reads or writes it.
+// NameHighlight and DescriptionHighlight are only set on search results.
+//
+// Attributes hold values for the attributes of the product's category, and
+// Tags are replaced on save unless nil.
 type Product struct {
-	ID            uint      `json:"id" gorm:"primarykey"`
-	Name          string    `json:"name" gorm:"not null"`
-	Description   string    `json:"description" gorm:"not null"`
-	Price         int       `json:"price" gorm:"not null"`
-	StockQuantity int       `json:"stock_quantity" gorm:"not null"`
-	IsActive      bool      `json:"is_active" gorm:"not null"`
-	CategoryID    uint      `json:"category_id" gorm:"not null;index"`
-	Category      Category  `json:"category" gorm:"foreignKey:CategoryID"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ID                   uint              `json:"id" gorm:"primarykey"`
+	Name                 string            `json:"name" gorm:"not null;index:idx_products_name_trgm,type:gin,expression:name gin_trgm_ops;uniqueIndex:idx_products_category_name,priority:2,expression:lower(name)"`
+	Slug                 string            `json:"slug" gorm:"not null;uniqueIndex"`
+	Description          string            `json:"description" gorm:"not null"`
+	SearchVector         string            `json:"-" gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector('indonesian', name), 'A') || setweight(to_tsvector('english', name), 'A') || setweight(to_tsvector('indonesian', description), 'B') || setweight(to_tsvector('english', description), 'B')) STORED;index:idx_products_search,type:gin;<-:false;->:false"`
+	NameHighlight        string            `json:"name_highlight,omitempty" gorm:"->;-:migration"`
+	DescriptionHighlight string            `json:"description_highlight,omitempty" gorm:"->;-:migration"`
+	Price                money.Money       `json:"price" gorm:"embedded"`
+	EffectivePrice       money.Money       `json:"effective_price" gorm:"-"`
+	EffectiveAmount      int64             `json:"-" gorm:"column:effective_price;->;-:migration"`
+	ListPrice            *money.Money      `json:"list_price,omitempty" gorm:"-"`
+	ListAmount           *int64            `json:"-" gorm:"column:list_price;->;-:migration"`
+	StockQuantity        int               `json:"stock_quantity" gorm:"not null;check:stock_quantity >= 0"`
+	AvailableQuantity    int               `json:"available_quantity" gorm:"->;-:migration"`
+	ReorderThreshold     *int              `json:"reorder_threshold" gorm:"check:reorder_threshold >= 0"`
+	IsActive             bool              `json:"is_active" gorm:"not null"`
+	CategoryID           uint              `json:"category_id" gorm:"not null;index;uniqueIndex:idx_products_category_name,priority:1"`
+	Category             Category          `json:"category" gorm:"foreignKey:CategoryID"`
+	Variants             []ProductVariant  `json:"variants" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	Media                []ProductMedia    `json:"media" gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE"`
+	Tags                 []Tag             `json:"tags" gorm:"many2many:product_tags;constraint:OnDelete:CASCADE"`
+	Attributes           ProductAttributes `json:"attributes" gorm:"type:jsonb;not null;default:'{}';index:idx_products_attributes,type:gin,expression:attributes jsonb_path_ops"`
+	CreatedAt            time.Time         `json:"created_at"`
+	UpdatedAt            time.Time         `json:"updated_at"`
+}
+
+// AfterFind gives the effective price selected into EffectiveAmount the
+// product's currency.
+func (p *Product) AfterFind(tx *gorm.DB) error {
+	p.EffectivePrice = money.New(p.EffectiveAmount, p.Price.Currency)
+	return nil
 }
 
 type ProductRepository interface {
 	GetAll(ctx context.Context) ([]Product, error)
-	GetAllPaginated(ctx context.Context, params dto.ProductFilterParams, pq dto.PaginationQuery) ([]Product, int64, error)
+	GetAllPaginated(ctx context.Context, params dto.ProductFilterParams, priceList *PriceList, pq dto.PaginationQuery, proj dto.Projection) ([]Product, int64, error)
+	CountByCategory(ctx context.Context, params dto.ProductFilterParams, priceList *PriceList) ([]dto.CategoryFacet, error)
+	CountByPriceBucket(ctx context.Context, params dto.ProductFilterParams, priceList *PriceList, currency string, bounds []int64) ([]int64, error)
+	CountByStock(ctx context.Context, params dto.ProductFilterParams, priceList *PriceList) (*dto.StockFacet, error)
 	GetProductReport(ctx context.Context) (*dto.ProductReportResponse, error)
+	StreamForExport(ctx context.Context, params dto.ProductFilterParams, fn func(row dto.ProductExportRow) error) error
 	GetByID(ctx context.Context, id int) (*Product, error)
+	GetByIDProjected(ctx context.Context, id int, priceList *PriceList, proj dto.Projection) (*Product, error)
+	GetBySlug(ctx context.Context, slug string, proj dto.Projection) (*Product, error)
+	Suggest(ctx context.Context, q string, limit int) ([]dto.ProductSuggestion, error)
 	Create(ctx context.Context, product *Product) error
 	Edit(ctx context.Context, product *Product) error
-	Delete(ctx context.Context, id int) error
+	Delete(ctx context.Context, id int) ([]ProductMedia, error)
+	GetExisting(ctx context.Context, ids []uint) (map[uint]*Product, error)
+	// GetByCategoryAndName returns the product of the category with the
+	// name, ignoring case, with the columns GetExisting reads.
+	GetByCategoryAndName(ctx context.Context, categoryID uint, name string) (*Product, error)
+	UpsertBatch(ctx context.Context, products []*Product) (created int, updated int, err error)
+	Transaction(ctx context.Context, fn func(repo ProductRepository) error) error
 }
 
 type ProductUsecase interface {
 	GetAllProducts(ctx context.Context) ([]Product, error)
-	GetAllProductsPaginated(ctx context.Context, params dto.ProductFilterParams, pq dto.PaginationQuery) (*dto.PaginatedResponse, error)
+	GetAllProductsPaginated(ctx context.Context, params dto.ProductFilterParams, pq dto.PaginationQuery, fq dto.ProductFacetQuery, proj dto.Projection) (*dto.PaginatedResponse, *dto.ProductFacets, error)
 	GetProductReport(ctx context.Context) (*dto.ProductReportResponse, error)
-	GetProductByID(ctx context.Context, id int) (*Product, error)
+	CheckExportFilters(ctx context.Context, params *dto.ProductFilterParams) error
+	ExportProducts(ctx context.Context, params dto.ProductFilterParams, fn func(row dto.ProductExportRow) error) error
+	GetProductByID(ctx context.Context, id int, priceList string, proj dto.Projection) (*Product, error)
+	GetProductBySlug(ctx context.Context, slug string, proj dto.Projection) (*Product, error)
+	SuggestProducts(ctx context.Context, query dto.ProductSuggestQuery) ([]dto.ProductSuggestion, error)
 	CreateProduct(ctx context.Context, product *Product) error
-	EditProduct(ctx context.Context, id int, req *dto.UpdateProductRequest) (*Product, error)
+	ReplaceProduct(ctx context.Context, id int, req *dto.ReplaceProductRequest) (*Product, error)
+	PatchProduct(ctx context.Context, id int, patch Patch) (*Product, error)
 	DeleteProduct(ctx context.Context, id int) error
+	BatchProducts(ctx context.Context, req *dto.BatchProductRequest) (*dto.BatchProductResponse, error)
 }
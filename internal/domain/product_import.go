@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/dto"
+)
+
+var ErrImportJobNotFound = errors.New("import job not found")
+
+// RequestValidator checks a request DTO against its binding rules, so code
+// outside the HTTP layer applies exactly the same rules as the handlers. It
+// returns the failing fields with a message for each, or nil when valid, and
+// an error when a rule that looks up stored data could not run.
+type RequestValidator interface {
+	ValidateStruct(ctx context.Context, obj interface{}) (map[string]string, error)
+	// Message renders the validation message of key, a validation tag or a
+	// check made outside the rules, in the locale of ctx.
+	Message(ctx context.Context, key string, params ...string) string
+}
+
+type ProductImportUsecase interface {
+	StartImport(ctx context.Context, rows []dto.ImportProductRow, opts dto.ImportOptions) (*dto.ImportJob, error)
+	GetImportJob(ctx context.Context, id string) (*dto.ImportJob, error)
+}
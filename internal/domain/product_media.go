@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrMediaNotFound    = errors.New("media not found")
+	ErrMediaTooLarge    = errors.New("media file is too large")
+	ErrMediaUnsupported = errors.New("media must be a JPEG, PNG or GIF image")
+	ErrMediaOrder       = errors.New("media order must list every media of the product once")
+)
+
+// ProductMedia is an image of a product. The file and its thumbnail live in
+// a MediaStorage under StorageKey and ThumbnailKey; URL and ThumbnailURL are
+// where clients fetch them and are set on reads, never stored. Media are
+// shown by Position, and at most one per product is the primary image.
+type ProductMedia struct {
+	ID           uint      `json:"id" gorm:"primarykey"`
+	ProductID    uint      `json:"product_id" gorm:"not null;index;uniqueIndex:idx_product_media_primary,where:is_primary;check:chk_product_media_position,position >= 0"`
+	StorageKey   string    `json:"-" gorm:"not null;uniqueIndex"`
+	ThumbnailKey string    `json:"-" gorm:"not null"`
+	URL          string    `json:"url" gorm:"-"`
+	ThumbnailURL string    `json:"thumbnail_url" gorm:"-"`
+	ContentType  string    `json:"content_type" gorm:"not null"`
+	Size         int64     `json:"size" gorm:"not null"`
+	Width        int       `json:"width" gorm:"not null"`
+	Height       int       `json:"height" gorm:"not null"`
+	Position     int       `json:"position" gorm:"not null"`
+	IsPrimary    bool      `json:"is_primary" gorm:"not null;default:false"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// MediaStorage keeps media files by key. Keys are slash-separated paths such
+// as "products/1/3f2a.jpg".
+type MediaStorage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL returns where clients fetch the file.
+	URL(key string) string
+}
+
+type ProductMediaRepository interface {
+	GetByProductID(ctx context.Context, productID int) ([]ProductMedia, error)
+	GetByID(ctx context.Context, productID int, id int) (*ProductMedia, error)
+	Create(ctx context.Context, media *ProductMedia) error
+	Delete(ctx context.Context, productID int, id int) (*ProductMedia, error)
+	Reorder(ctx context.Context, productID int, ids []uint) error
+	SetPrimary(ctx context.Context, productID int, id int) error
+}
+
+type ProductMediaUsecase interface {
+	GetMedia(ctx context.Context, productID int) ([]ProductMedia, error)
+	UploadMedia(ctx context.Context, productID int, file io.Reader, primary bool) (*ProductMedia, error)
+	ReorderMedia(ctx context.Context, productID int, ids []uint) ([]ProductMedia, error)
+	SetPrimaryMedia(ctx context.Context, productID int, id int) (*ProductMedia, error)
+	DeleteMedia(ctx context.Context, productID int, id int) error
+	OpenFile(ctx context.Context, key string) (io.ReadCloser, error)
+}
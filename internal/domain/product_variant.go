@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/money"
+	"time"
+)
+
+var (
+	ErrVariantNotFound         = errors.New("variant not found")
+	ErrDuplicateVariantOptions = errors.New("product already has a variant with these options")
+)
+
+// VariantOptions are the option values that tell the variants of a product
+// apart, e.g. {"size": "M", "color": "black"}. They are stored as JSONB.
+type VariantOptions map[string]string
+
+func (o VariantOptions) Value() (driver.Value, error) {
+	if o == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(o)
+	return string(data), err
+}
+
+func (o *VariantOptions) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*o = VariantOptions{}
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into VariantOptions", value)
+	}
+	return json.Unmarshal(data, o)
+}
+
+// ProductVariant is a sellable version of a product, such as one size of a
+// t-shirt, with its own SKU, price and stock. The stock of a product is its
+// own StockQuantity plus the stock of all its variants. AvailableQuantity
+// is the variant's stock minus active reservation holds; it is computed by
+// queries that select it and never written.
+type ProductVariant struct {
+	ID                uint           `json:"id" gorm:"primarykey"`
+	ProductID         uint           `json:"product_id" gorm:"not null;index"`
+	SKU               string         `json:"sku" gorm:"column:sku;not null;uniqueIndex"`
+	Options           VariantOptions `json:"options" gorm:"type:jsonb;not null;default:'{}'"`
+	Price             money.Money    `json:"price" gorm:"embedded"`
+	StockQuantity     int            `json:"stock_quantity" gorm:"not null;check:stock_quantity >= 0"`
+	AvailableQuantity int            `json:"available_quantity" gorm:"->;-:migration"`
+	IsActive          bool           `json:"is_active" gorm:"not null"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+type ProductVariantRepository interface {
+	GetByProductID(ctx context.Context, productID int) ([]ProductVariant, error)
+	GetByID(ctx context.Context, productID int, id int) (*ProductVariant, error)
+	Create(ctx context.Context, variant *ProductVariant) error
+	Edit(ctx context.Context, variant *ProductVariant) error
+	Delete(ctx context.Context, productID int, id int) error
+}
+
+type ProductVariantUsecase interface {
+	GetVariants(ctx context.Context, productID int) ([]ProductVariant, error)
+	GetVariant(ctx context.Context, productID int, id int) (*ProductVariant, error)
+	CreateVariant(ctx context.Context, productID int, req *dto.VariantRequest) (*ProductVariant, error)
+	ReplaceVariant(ctx context.Context, productID int, id int, req *dto.ReplaceVariantRequest) (*ProductVariant, error)
+	DeleteVariant(ctx context.Context, productID int, id int) error
+}
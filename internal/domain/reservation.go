@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/dto"
+	"time"
+)
+
+var (
+	ErrReservationNotFound = errors.New("reservation not found")
+	ErrReservationExpired  = errors.New("reservation has expired")
+	ErrReservationClosed   = errors.New("reservation is no longer active")
+)
+
+// Reservation holds stock for a checkout until it expires. An active hold
+// lowers the available quantity of its products and variants without
+// touching their stock; confirming it turns the items into sales.
+type Reservation struct {
+	ID        uint              `json:"id" gorm:"primarykey"`
+	Status    string            `json:"status" gorm:"not null;index:idx_reservations_status_expires_at,priority:1"`
+	Reference string            `json:"reference" gorm:"not null"`
+	Actor     string            `json:"actor" gorm:"not null"`
+	ExpiresAt time.Time         `json:"expires_at" gorm:"not null;index:idx_reservations_status_expires_at,priority:2"`
+	Items     []ReservationItem `json:"items" gorm:"foreignKey:ReservationID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// ReservationItem is the quantity of a product, or of one of its variants,
+// held by a reservation.
+type ReservationItem struct {
+	ID            uint            `json:"id" gorm:"primarykey"`
+	ReservationID uint            `json:"reservation_id" gorm:"not null;index"`
+	ProductID     uint            `json:"product_id" gorm:"not null;index"`
+	Product       *Product        `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	VariantID     *uint           `json:"variant_id" gorm:"index"`
+	Variant       *ProductVariant `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Quantity      int             `json:"quantity" gorm:"not null;check:quantity > 0"`
+}
+
+type ReservationRepository interface {
+	GetByID(ctx context.Context, id int) (*Reservation, error)
+	Create(ctx context.Context, reservation *Reservation) error
+	Confirm(ctx context.Context, id int, warehouseID *uint) (*Reservation, error)
+	Release(ctx context.Context, id int) (*Reservation, error)
+	ExpireHolds(ctx context.Context, now time.Time) (int64, error)
+}
+
+type ReservationUsecase interface {
+	GetReservation(ctx context.Context, id int) (*Reservation, error)
+	CreateReservation(ctx context.Context, req *dto.ReservationRequest) (*Reservation, error)
+	ConfirmReservation(ctx context.Context, id int, req *dto.ConfirmReservationRequest) (*Reservation, error)
+	ReleaseReservation(ctx context.Context, id int) (*Reservation, error)
+	ExpireReservations(ctx context.Context) (int64, error)
+	RunSweeper(ctx context.Context, interval time.Duration)
+}
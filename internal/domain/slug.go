@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrSlugNotFound = errors.New("slug not found")
+	ErrConflict     = errors.New("conflicts with an existing record")
+)
+
+const (
+	SlugEntityProduct  = "product"
+	SlugEntityCategory = "category"
+)
+
+// SlugRedirect remembers a slug an entity used before it was renamed, so old
+// URLs keep resolving to it. A retired slug stays reserved for its entity.
+type SlugRedirect struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	EntityType string    `json:"entity_type" gorm:"not null;uniqueIndex:idx_slug_redirects_entity_slug"`
+	Slug       string    `json:"slug" gorm:"not null;uniqueIndex:idx_slug_redirects_entity_slug"`
+	EntityID   uint      `json:"entity_id" gorm:"not null;index"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"test-elabram/internal/dto"
+	"time"
+)
+
+// StockAlert is raised when the stock of a product falls to its reorder
+// threshold. It stays open, and no further alert is sent for the product,
+// until the stock recovers above the threshold.
+type StockAlert struct {
+	ID               uint       `json:"id" gorm:"primarykey"`
+	ProductID        uint       `json:"product_id" gorm:"not null;uniqueIndex:idx_stock_alerts_open,where:resolved_at IS NULL"`
+	Product          *Product   `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	StockQuantity    int        `json:"stock_quantity" gorm:"not null"`
+	ReorderThreshold int        `json:"reorder_threshold" gorm:"not null"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ResolvedAt       *time.Time `json:"resolved_at"`
+}
+
+type StockAlertRepository interface {
+	GetStockStatus(ctx context.Context, productIDs []uint) ([]dto.StockStatus, error)
+	GetLowStock(ctx context.Context, pq dto.PaginationQuery) ([]dto.StockStatus, int64, error)
+	OpenAlert(ctx context.Context, status dto.StockStatus) (bool, error)
+	ResolveAlert(ctx context.Context, productID uint) (bool, error)
+	DiscardAlert(ctx context.Context, productID uint) error
+}
+
+// StockAlertNotifier delivers low-stock alerts, e.g. by webhook or e-mail.
+type StockAlertNotifier interface {
+	Name() string
+	Notify(ctx context.Context, status dto.StockStatus) error
+}
+
+// StockObserver is told about products whose stock or reorder threshold may
+// have changed. It must not block the caller.
+type StockObserver interface {
+	StockChanged(productIDs ...uint)
+}
+
+type StockAlertUsecase interface {
+	StockObserver
+	GetLowStockProducts(ctx context.Context, pq dto.PaginationQuery) (*dto.PaginatedResponse, error)
+	Evaluate(ctx context.Context, productIDs []uint) error
+	Run(ctx context.Context, interval time.Duration)
+}
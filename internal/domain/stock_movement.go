@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/dto"
+	"time"
+)
+
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// StockMovement is an entry of the inventory ledger. Quantity is the signed
+// change it made to the stock of the product, or of one of its variants, in
+// a warehouse, and BalanceAfter the total stock right after it. Stock
+// quantities and levels are only ever changed together with a movement, so
+// the ledger always adds up to them. A transfer is a pair of movements that
+// cancel out in the total.
+type StockMovement struct {
+	ID           uint            `json:"id" gorm:"primarykey"`
+	ProductID    uint            `json:"product_id" gorm:"not null;index"`
+	Product      *Product        `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	VariantID    *uint           `json:"variant_id" gorm:"index"`
+	Variant      *ProductVariant `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	WarehouseID  *uint           `json:"warehouse_id" gorm:"index"`
+	Warehouse    *Warehouse      `json:"-" gorm:"constraint:OnDelete:SET NULL"`
+	Type         string          `json:"type" gorm:"not null"`
+	Quantity     int             `json:"quantity" gorm:"not null"`
+	BalanceAfter int             `json:"balance_after" gorm:"not null"`
+	Reason       string          `json:"reason" gorm:"not null"`
+	Actor        string          `json:"actor" gorm:"not null"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+type StockMovementRepository interface {
+	Record(ctx context.Context, movement *StockMovement) error
+	Transfer(ctx context.Context, out *StockMovement, in *StockMovement) error
+	GetByProductID(ctx context.Context, productID int, query dto.StockMovementQuery, pq dto.PaginationQuery) ([]StockMovement, int64, error)
+}
+
+type StockMovementUsecase interface {
+	RecordMovement(ctx context.Context, productID int, req *dto.StockMovementRequest) (*StockMovement, error)
+	TransferStock(ctx context.Context, req *dto.StockTransferRequest) ([]StockMovement, error)
+	GetMovements(ctx context.Context, productID int, query dto.StockMovementQuery, pq dto.PaginationQuery) (*dto.PaginatedResponse, error)
+}
+
+type actorKey struct{}
+
+// SystemActor is recorded for changes made outside of an HTTP request, such
+// as seeding.
+const SystemActor = "system"
+
+// WithActor returns a context that attributes changes to actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor set with WithActor, or SystemActor.
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return SystemActor
+}
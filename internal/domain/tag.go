@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/dto"
+	"time"
+)
+
+var ErrTagNotFound = errors.New("tag not found")
+
+// Tag labels products across categories, e.g. "halal" or "new-arrival".
+// Products refer to tags by slug.
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Name      string    `json:"name" gorm:"not null"`
+	Slug      string    `json:"slug" gorm:"not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TagRefs returns tags that only carry the given slugs, for a usecase to
+// resolve. Nil stays nil.
+func TagRefs(slugs []string) []Tag {
+	if slugs == nil {
+		return nil
+	}
+	tags := make([]Tag, len(slugs))
+	for i, slug := range slugs {
+		tags[i] = Tag{Slug: slug}
+	}
+	return tags
+}
+
+type TagRepository interface {
+	GetAll(ctx context.Context) ([]Tag, error)
+	GetByID(ctx context.Context, id int) (*Tag, error)
+	GetBySlugs(ctx context.Context, slugs []string) ([]Tag, error)
+	Create(ctx context.Context, tag *Tag) error
+	Edit(ctx context.Context, tag *Tag) error
+	Delete(ctx context.Context, id int) error
+}
+
+type TagUsecase interface {
+	GetAllTags(ctx context.Context) ([]Tag, error)
+	CreateTag(ctx context.Context, req *dto.TagRequest) (*Tag, error)
+	ReplaceTag(ctx context.Context, id int, req *dto.TagRequest) (*Tag, error)
+	DeleteTag(ctx context.Context, id int) error
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/locale"
+	"time"
+)
+
+var (
+	ErrTranslationNotFound = errors.New("translation not found")
+	ErrUnsupportedLocale   = errors.New("unsupported locale")
+)
+
+// ProductTranslation is the name and description of a product in a locale
+// other than locale.Default. SearchVector indexes them in the configuration
+// of the locale, like Product.SearchVector.
+type ProductTranslation struct {
+	ProductID    uint      `json:"product_id" gorm:"primaryKey;autoIncrement:false"`
+	Product      *Product  `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Locale       string    `json:"locale" gorm:"primaryKey"`
+	Name         string    `json:"name" gorm:"not null;index:idx_product_translations_name_trgm,type:gin,expression:name gin_trgm_ops"`
+	Description  string    `json:"description" gorm:"not null"`
+	SearchVector string    `json:"-" gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector(CASE locale WHEN 'id' THEN 'indonesian'::regconfig ELSE 'english'::regconfig END, name), 'A') || setweight(to_tsvector(CASE locale WHEN 'id' THEN 'indonesian'::regconfig ELSE 'english'::regconfig END, description), 'B')) STORED;index:idx_product_translations_search,type:gin;<-:false;->:false"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CategoryTranslation is the name and description of a category in a locale
+// other than locale.Default.
+type CategoryTranslation struct {
+	CategoryID  uint      `json:"category_id" gorm:"primaryKey;autoIncrement:false"`
+	Category    *Category `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Locale      string    `json:"locale" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"not null;index:idx_category_translations_name_trgm,type:gin,expression:name gin_trgm_ops"`
+	Description string    `json:"description" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type localeKey struct{}
+
+// WithLocale returns a context whose reads show content in locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale set with WithLocale, or
+// locale.Default.
+func LocaleFromContext(ctx context.Context) string {
+	if l, ok := ctx.Value(localeKey{}).(string); ok && l != "" {
+		return l
+	}
+	return locale.Default
+}
+
+type TranslationRepository interface {
+	GetProductTranslations(ctx context.Context, productID int) ([]ProductTranslation, error)
+	// ProductTranslations returns the translations into locale of the given
+	// products that have one, by product ID.
+	ProductTranslations(ctx context.Context, locale string, productIDs []uint) (map[uint]ProductTranslation, error)
+	SaveProductTranslation(ctx context.Context, translation *ProductTranslation) error
+	DeleteProductTranslation(ctx context.Context, productID int, locale string) error
+	GetCategoryTranslations(ctx context.Context, categoryID int) ([]CategoryTranslation, error)
+	CategoryTranslations(ctx context.Context, locale string, categoryIDs []uint) (map[uint]CategoryTranslation, error)
+	SaveCategoryTranslation(ctx context.Context, translation *CategoryTranslation) error
+	DeleteCategoryTranslation(ctx context.Context, categoryID int, locale string) error
+	// GetMissingProducts lists the products lacking a translation into one
+	// of locales, one item per product and locale.
+	GetMissingProducts(ctx context.Context, locales []string, pq dto.PaginationQuery) ([]dto.MissingTranslation, int64, error)
+	GetMissingCategories(ctx context.Context, locales []string, pq dto.PaginationQuery) ([]dto.MissingTranslation, int64, error)
+}
+
+type TranslationUsecase interface {
+	GetProductTranslations(ctx context.Context, productID int) ([]ProductTranslation, error)
+	SaveProductTranslation(ctx context.Context, productID int, locale string, req *dto.TranslationRequest) (*ProductTranslation, error)
+	DeleteProductTranslation(ctx context.Context, productID int, locale string) error
+	GetCategoryTranslations(ctx context.Context, categoryID int) ([]CategoryTranslation, error)
+	SaveCategoryTranslation(ctx context.Context, categoryID int, locale string, req *dto.TranslationRequest) (*CategoryTranslation, error)
+	DeleteCategoryTranslation(ctx context.Context, categoryID int, locale string) error
+	GetMissingProductTranslations(ctx context.Context, query dto.MissingTranslationQuery, pq dto.PaginationQuery) (*dto.PaginatedResponse, error)
+	GetMissingCategoryTranslations(ctx context.Context, query dto.MissingTranslationQuery, pq dto.PaginationQuery) (*dto.PaginatedResponse, error)
+}
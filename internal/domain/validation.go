@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+// ValidationRepository answers the lookups of the validation rules that
+// depend on stored data.
+type ValidationRepository interface {
+	CategoryExists(ctx context.Context, id uint) (bool, error)
+	// ProductNameTaken reports whether a product of the category other than
+	// excludeID has the name, ignoring case.
+	ProductNameTaken(ctx context.Context, categoryID uint, name string, excludeID uint) (bool, error)
+}
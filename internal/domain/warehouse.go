@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/dto"
+	"time"
+)
+
+var (
+	ErrWarehouseNotFound   = errors.New("warehouse not found")
+	ErrWarehouseNotEmpty   = errors.New("warehouse still holds stock")
+	ErrDefaultWarehouse    = errors.New("the default warehouse cannot be deleted")
+	ErrNoDefaultWarehouse  = errors.New("no default warehouse is configured")
+	ErrSameWarehouseTarget = errors.New("source and destination warehouse must differ")
+	ErrWarehouseCodeTaken  = errors.New("warehouse code is already in use")
+	ErrDefaultChanged      = errors.New("another warehouse was made the default at the same time")
+)
+
+// Warehouse is a stock location. Stock changes that do not name a warehouse,
+// such as setting stock_quantity on a product, apply to the default one.
+type Warehouse struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	Code      string    `json:"code" gorm:"not null;uniqueIndex"`
+	Name      string    `json:"name" gorm:"not null"`
+	IsDefault bool      `json:"is_default" gorm:"not null;default:false;uniqueIndex:idx_warehouses_default,where:is_default"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// StockLevel is the stock of a product, or of one of its variants, held in
+// one warehouse. VariantID is 0 for the product's own stock. The stock
+// quantity of a product or variant is the sum of its levels.
+type StockLevel struct {
+	ID          uint       `json:"id" gorm:"primarykey"`
+	WarehouseID uint       `json:"warehouse_id" gorm:"not null;uniqueIndex:idx_stock_levels_location,priority:1"`
+	Warehouse   *Warehouse `json:"warehouse,omitempty" gorm:"constraint:OnDelete:CASCADE"`
+	ProductID   uint       `json:"product_id" gorm:"not null;uniqueIndex:idx_stock_levels_location,priority:2;index"`
+	Product     *Product   `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	VariantID   uint       `json:"variant_id,omitempty" gorm:"not null;default:0;uniqueIndex:idx_stock_levels_location,priority:3"`
+	Quantity    int        `json:"quantity" gorm:"not null;check:quantity >= 0"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+type WarehouseRepository interface {
+	GetAll(ctx context.Context) ([]Warehouse, error)
+	GetByID(ctx context.Context, id int) (*Warehouse, error)
+	Create(ctx context.Context, warehouse *Warehouse) error
+	Edit(ctx context.Context, warehouse *Warehouse) error
+	Delete(ctx context.Context, id int) error
+	GetStockLevels(ctx context.Context, warehouseID int) ([]StockLevel, error)
+	GetProductStockLevels(ctx context.Context, productID int) ([]StockLevel, error)
+}
+
+type WarehouseUsecase interface {
+	GetAllWarehouses(ctx context.Context) ([]Warehouse, error)
+	GetWarehouseByID(ctx context.Context, id int) (*Warehouse, error)
+	CreateWarehouse(ctx context.Context, req *dto.WarehouseRequest) (*Warehouse, error)
+	ReplaceWarehouse(ctx context.Context, id int, req *dto.WarehouseRequest) (*Warehouse, error)
+	DeleteWarehouse(ctx context.Context, id int) error
+	GetWarehouseStock(ctx context.Context, id int) ([]StockLevel, error)
+	GetProductStockLevels(ctx context.Context, productID int) ([]StockLevel, error)
+}
@@ -0,0 +1,17 @@
+package dto
+
+// CategoryAttributeRequest defines an attribute of a category. Options lists
+// the values of an enum attribute and must be empty for the other types.
+type CategoryAttributeRequest struct {
+	Name     string   `json:"name" binding:"required,attribute_name"`
+	Type     string   `json:"type" binding:"required,oneof=string number boolean enum"`
+	Options  []string `json:"options" binding:"omitempty,max=100,unique,dive,required,max=64"`
+	Required bool     `json:"required"`
+}
+
+// UpdateCategoryAttributeRequest replaces what can change of an attribute.
+// Its name and type are fixed once products may hold values of it.
+type UpdateCategoryAttributeRequest struct {
+	Options  []string `json:"options" binding:"omitempty,max=100,unique,dive,required,max=64"`
+	Required bool     `json:"required"`
+}
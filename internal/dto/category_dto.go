@@ -1,11 +1,33 @@
 package dto
 
 type CreateCategoryRequest struct {
-	Name        string `json:"name" binding:"required"`
-	Description string `json:"description" binding:"required"`
+	Name             string `json:"name" binding:"required"`
+	Description      string `json:"description" binding:"required"`
+	ParentID         *uint  `json:"parent_id" binding:"omitempty,gt=0"`
+	ReorderThreshold *int   `json:"reorder_threshold" binding:"omitempty,gte=0"`
 }
 
-type UpdateCategoryRequest struct {
-	Name        string `json:"name" binding:"omitempty"`
-	Description string `json:"description" binding:"omitempty"`
+// ReplaceCategoryRequest is the full representation accepted by PUT and the
+// document that PATCH requests are applied to. An empty description clears
+// it.
+type ReplaceCategoryRequest struct {
+	Name             string `json:"name" binding:"required"`
+	Description      string `json:"description"`
+	ReorderThreshold *int   `json:"reorder_threshold" binding:"omitempty,gte=0"`
+}
+
+// MoveCategoryRequest moves a category under ParentID, or to the root when
+// it is null.
+type MoveCategoryRequest struct {
+	ParentID *uint `json:"parent_id" binding:"omitempty,gt=0"`
+}
+
+type CategoryTreeNode struct {
+	ID          uint                `json:"id"`
+	Name        string              `json:"name"`
+	Slug        string              `json:"slug"`
+	Description string              `json:"description"`
+	ParentID    *uint               `json:"parent_id"`
+	Path        string              `json:"path"`
+	Children    []*CategoryTreeNode `json:"children"`
 }
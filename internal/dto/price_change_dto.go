@@ -0,0 +1,10 @@
+package dto
+
+// PriceChangeRequest schedules a price change. EffectiveFrom is an RFC 3339
+// timestamp, or a local "2006-01-02T15:04:05" or "2006-01-02 15:04" time in
+// the store's time zone, and must be in the future.
+type PriceChangeRequest struct {
+	Price         MoneyRequest `json:"price"`
+	EffectiveFrom string       `json:"effective_from" binding:"required"`
+	Reason        string       `json:"reason" binding:"max=255"`
+}
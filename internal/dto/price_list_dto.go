@@ -0,0 +1,21 @@
+package dto
+
+// PriceListRequest creates a price list. The currency cannot change later,
+// as the overrides in the list are in it.
+type PriceListRequest struct {
+	Code     string `json:"code" binding:"required,max=64"`
+	Name     string `json:"name" binding:"required,max=255"`
+	Currency string `json:"currency" binding:"required,currency"`
+}
+
+// PriceListPriceRequest sets the price of a product in a price list, in the
+// list's currency.
+type PriceListPriceRequest struct {
+	Price MoneyRequest `json:"price"`
+}
+
+// ExchangeRateRequest sets the rate of a currency pair as a decimal string,
+// e.g. "0.0000625" for IDR to USD.
+type ExchangeRateRequest struct {
+	Rate string `json:"rate" binding:"required,rate"`
+}
@@ -1,21 +1,119 @@
 package dto
 
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"test-elabram/internal/filter"
+	"test-elabram/internal/money"
+	"time"
+)
+
+// MoneyRequest is a price sent by a client, with the amount as a decimal
+// string such as "19.99" that has at most the decimal places of the currency.
+type MoneyRequest struct {
+	Amount   string `json:"amount" binding:"required,price=Currency"`
+	Currency string `json:"currency" binding:"required,currency"`
+}
+
+// Money returns the validated price.
+func (m MoneyRequest) Money() money.Money {
+	price, _ := money.Parse(m.Amount, m.Currency)
+	return price
+}
+
+// NewMoneyRequest returns the request form of a price.
+func NewMoneyRequest(price money.Money) MoneyRequest {
+	return MoneyRequest{Amount: price.String(), Currency: price.Currency}
+}
+
 type CreateProductRequest struct {
-	Name          string `json:"name" binding:"required"`
-	Description   string `json:"description" binding:"required"`
-	Price         int    `json:"price" binding:"required,gt=0"`
-	StockQuantity int    `json:"stock_quantity" binding:"required,gte=0"`
-	IsActive      bool   `json:"is_active"`
-	CategoryID    uint   `json:"category_id" binding:"required,gt=0"`
+	Name             string                 `json:"name" binding:"required"`
+	Description      string                 `json:"description" binding:"required"`
+	Price            MoneyRequest           `json:"price"`
+	StockQuantity    int                    `json:"stock_quantity" binding:"required,gte=0"`
+	IsActive         bool                   `json:"is_active"`
+	CategoryID       uint                   `json:"category_id" binding:"required,gt=0"`
+	ReorderThreshold *int                   `json:"reorder_threshold" binding:"omitempty,gte=0"`
+	Tags             []string               `json:"tags" binding:"omitempty,max=50,unique,dive,required"`
+	Attributes       map[string]interface{} `json:"attributes"`
+}
+
+// ReplaceProductRequest is the full representation accepted by PUT and the
+// document that PATCH requests are applied to. StockQuantity may not be set:
+// stock only changes through stock movements.
+type ReplaceProductRequest struct {
+	Name             string                 `json:"name" binding:"required"`
+	Description      string                 `json:"description" binding:"required"`
+	Price            MoneyRequest           `json:"price"`
+	StockQuantity    *int                   `json:"stock_quantity,omitempty" binding:"ledger"`
+	IsActive         bool                   `json:"is_active"`
+	CategoryID       uint                   `json:"category_id" binding:"required,gt=0"`
+	ReorderThreshold *int                   `json:"reorder_threshold" binding:"omitempty,gte=0"`
+	Tags             []string               `json:"tags" binding:"omitempty,max=50,unique,dive,required"`
+	Attributes       map[string]interface{} `json:"attributes"`
 }
 
+// UpdateProductRequest changes the fields it sets. Tags and Attributes, when
+// set, replace the product's. StockQuantity may not be set, like in
+// ReplaceProductRequest.
 type UpdateProductRequest struct {
-	Name          *string `json:"name"`
-	Description   *string `json:"description"`
-	Price         *int    `json:"price" binding:"omitempty,gt=0"`
-	StockQuantity *int    `json:"stock_quantity" binding:"omitempty,gte=0"`
-	IsActive      *bool   `json:"is_active"`
-	CategoryID    *uint   `json:"category_id" binding:"omitempty,gt=0"`
+	Name             *string                `json:"name"`
+	Description      *string                `json:"description"`
+	Price            *MoneyRequest          `json:"price"`
+	StockQuantity    *int                   `json:"stock_quantity" binding:"ledger"`
+	IsActive         *bool                  `json:"is_active"`
+	CategoryID       *uint                  `json:"category_id" binding:"omitempty,gt=0"`
+	ReorderThreshold *int                   `json:"reorder_threshold" binding:"omitempty,gte=0"`
+	Tags             []string               `json:"tags" binding:"omitempty,max=50,unique,dive,required"`
+	Attributes       map[string]interface{} `json:"attributes"`
+}
+
+const (
+	BatchModeAtomic     = "atomic"
+	BatchModeBestEffort = "best_effort"
+
+	BatchOpCreate = "create"
+	BatchOpUpdate = "update"
+	BatchOpDelete = "delete"
+
+	BatchStatusOK         = "ok"
+	BatchStatusFailed     = "failed"
+	BatchStatusRolledBack = "rolled_back"
+	BatchStatusSkipped    = "skipped"
+)
+
+type BatchProductRequest struct {
+	Mode       string                  `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	Operations []BatchProductOperation `json:"operations" binding:"required,min=1,max=500"`
+}
+
+// BatchProductOperation carries a CreateProductRequest or an
+// UpdateProductRequest in Data, depending on Op. Data is decoded per item so
+// a malformed item only fails itself.
+type BatchProductOperation struct {
+	Op   string          `json:"op"`
+	ID   int             `json:"id"`
+	Data json.RawMessage `json:"data"`
+}
+
+type BatchProductResult struct {
+	Index  int               `json:"index"`
+	Op     string            `json:"op"`
+	ID     uint              `json:"id,omitempty"`
+	Status string            `json:"status"`
+	Error  string            `json:"error,omitempty"`
+	Errors map[string]string `json:"errors,omitempty"`
+	Data   interface{}       `json:"data,omitempty"`
+}
+
+type BatchProductResponse struct {
+	Mode      string               `json:"mode"`
+	Succeeded int                  `json:"succeeded"`
+	Failed    int                  `json:"failed"`
+	Results   []BatchProductResult `json:"results"`
 }
 
 type PaginationQuery struct {
@@ -31,28 +129,251 @@ type PaginatedResponse struct {
 	TotalPages int         `json:"total_pages"`
 }
 
+// ProductFilterParams filters the product listing. Currency limits it to
+// products priced in that currency. PriceList selects the price list that
+// prices are given in. PriceMin and PriceMax are decimal amounts in the
+// currency of the price list, or else in Currency, or in the default
+// currency when both are empty; without a price list they only match
+// products priced in that currency. Q is a full-text search, whose results
+// sort by relevance unless SortBy says otherwise. Filter and Sort are
+// expressions of the filter package over ProductFilterFields and
+// ProductSortFields; Sort replaces SortBy and SortOrder when given. Tag
+// limits the listing to products with all the given tag slugs. Attr are
+// filter expressions over attribute values, whose types are looked up into
+// AttributeTypes before they are parsed. A minimum greater than its maximum
+// fails validation.
 type ProductFilterParams struct {
-	Name       string `form:"name"`
-	CategoryID *uint  `form:"category_id"`
-	PriceMin   *int   `form:"price_min"`
-	PriceMax   *int   `form:"price_max"`
-	StockMin   *int   `form:"stock_min"`
-	StockMax   *int   `form:"stock_max"`
-	SortBy     string `form:"sort_by,default=created_at"`
-	SortOrder  string `form:"sort_order,default=desc"`
+	Q                  string   `form:"q"`
+	Name               string   `form:"name"`
+	CategoryID         *uint    `form:"category_id"`
+	IncludeDescendants bool     `form:"include_descendants"`
+	Currency           string   `form:"currency" binding:"omitempty,currency"`
+	PriceList          string   `form:"price_list"`
+	PriceMin           *string  `form:"price_min"`
+	PriceMax           *string  `form:"price_max"`
+	StockMin           *int     `form:"stock_min"`
+	StockMax           *int     `form:"stock_max"`
+	WarehouseID        *uint    `form:"warehouse_id"`
+	SortBy             string   `form:"sort_by"`
+	SortOrder          string   `form:"sort_order,default=desc" binding:"omitempty,oneof=asc desc"`
+	Filter             []string `form:"filter"`
+	Sort               string   `form:"sort"`
+	Tag                []string `form:"tag"`
+	Attr               []string `form:"attr"`
+
+	AttributeTypes filter.Schema `form:"-"`
+}
+
+// ProductFilterFields are the fields filter expressions may test.
+var ProductFilterFields = filter.Schema{
+	"id":             filter.Int,
+	"name":           filter.String,
+	"category_id":    filter.Int,
+	"currency":       filter.String,
+	"is_active":      filter.Bool,
+	"stock_quantity": filter.Int,
+	"created_at":     filter.Time,
+	"updated_at":     filter.Time,
+}
+
+// ProductSortByColumns are the values of sort_by. relevance only applies to
+// a search.
+var ProductSortByColumns = []string{"name", "price", "stock_quantity", "created_at", "category_id", "relevance"}
+
+// ProductSortFields are the fields the listing may be sorted by. relevance
+// only applies to a search.
+var ProductSortFields = []string{"id", "name", "price", "stock_quantity", "category_id", "created_at", "updated_at", "relevance"}
+
+// Conditions parses the filter expressions.
+func (p ProductFilterParams) Conditions() ([]filter.Condition, error) {
+	return ProductFilterFields.Parse(p.Filter)
+}
+
+// AttributeConditions parses the attribute filter expressions against
+// AttributeTypes.
+func (p ProductFilterParams) AttributeConditions() ([]filter.Condition, error) {
+	return p.AttributeTypes.Parse(p.Attr)
+}
+
+// SortKeys parses the sort expression, or returns none when it is empty.
+func (p ProductFilterParams) SortKeys() ([]filter.SortKey, error) {
+	if p.Sort == "" {
+		return nil, nil
+	}
+	return filter.ParseSort(p.Sort, ProductSortFields)
+}
+
+// ExpressionErrors checks the filter, sort and attribute expressions, which
+// are only parsed after binding.
+func (p ProductFilterParams) ExpressionErrors() map[string]*filter.Error {
+	_, filterErr := p.Conditions()
+	_, sortErr := p.SortKeys()
+	_, attrErr := p.AttributeConditions()
+	errs := map[string]*filter.Error{}
+	for param, err := range map[string]error{"filter": filterErr, "sort": sortErr, "attr": attrErr} {
+		var fe *filter.Error
+		if errors.As(err, &fe) {
+			errs[param] = fe
+		}
+	}
+	return errs
+}
+
+// WithoutFilterOn returns the params without the filter expressions on the
+// given fields.
+func (p ProductFilterParams) WithoutFilterOn(fields ...string) ProductFilterParams {
+	kept := make([]string, 0, len(p.Filter))
+	for _, expr := range p.Filter {
+		field, _, _ := strings.Cut(expr, ":")
+		if !slices.Contains(fields, field) {
+			kept = append(kept, expr)
+		}
+	}
+	p.Filter = kept
+	return p
+}
+
+// PriceCurrency returns the currency PriceMin and PriceMax are in, given the
+// currency of the selected price list or "" when there is none.
+func (p ProductFilterParams) PriceCurrency(priceListCurrency string) string {
+	return cmp.Or(priceListCurrency, p.Currency, money.DefaultCurrency)
+}
+
+// InvalidPriceBounds checks PriceMin and PriceMax against the precision of
+// the currency they are in, which depends on the price list and is only
+// known after binding. It returns the parameters that do not fit it.
+func (p ProductFilterParams) InvalidPriceBounds(priceListCurrency string) []string {
+	currency := p.PriceCurrency(priceListCurrency)
+	var invalid []string
+	for field, amount := range map[string]*string{"price_min": p.PriceMin, "price_max": p.PriceMax} {
+		if amount == nil {
+			continue
+		}
+		if _, err := money.Parse(*amount, currency); err != nil {
+			invalid = append(invalid, field)
+		}
+	}
+	return invalid
+}
+
+// ProductFacetQuery asks the product listing for aggregations next to the
+// results, as a comma-separated list of facet names, e.g.
+// "category,price,stock".
+type ProductFacetQuery struct {
+	Facets string `form:"facets"`
+}
+
+// ProductFacets are the aggregations of the product listing. Each facet is
+// counted under all the listing's filters except its own, so it shows what
+// choosing another value would return. Only the requested facets are set.
+type ProductFacets struct {
+	Category []CategoryFacet `json:"category,omitempty"`
+	Price    *PriceFacet     `json:"price,omitempty"`
+	Stock    *StockFacet     `json:"stock,omitempty"`
+}
+
+// CategoryFacet counts the matching products in one category, not including
+// its child categories.
+type CategoryFacet struct {
+	CategoryID uint   `json:"category_id"`
+	Name       string `json:"name"`
+	Count      int64  `json:"count"`
+}
+
+// PriceFacet counts the matching products per price bucket, in the currency
+// the price range would be in.
+type PriceFacet struct {
+	Currency string        `json:"currency"`
+	Buckets  []PriceBucket `json:"buckets"`
+}
+
+// PriceBucket covers prices from Min up to but not including Max. The first
+// bucket has no Min and the last no Max.
+type PriceBucket struct {
+	Min   *money.Money `json:"min"`
+	Max   *money.Money `json:"max"`
+	Count int64        `json:"count"`
+}
+
+// StockFacet counts the matching products with and without stock.
+type StockFacet struct {
+	InStock    int64 `json:"in_stock"`
+	OutOfStock int64 `json:"out_of_stock"`
+}
+
+type ExportQuery struct {
+	Format string `form:"format,default=csv" binding:"oneof=csv ndjson xlsx"`
+}
+
+// ProductExportRow is one flattened catalog row of an export. In JSON the
+// price is a decimal string next to its currency, the form imports accept.
+type ProductExportRow struct {
+	ID            uint        `json:"id"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description"`
+	Price         money.Money `json:"-" gorm:"embedded"`
+	StockQuantity int         `json:"stock_quantity"`
+	IsActive      bool        `json:"is_active"`
+	CategoryID    uint        `json:"category_id"`
+	CategoryName  string      `json:"category_name"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// MarshalJSON writes the price as a decimal string and its currency in
+// place of the embedded money.Money.
+func (r ProductExportRow) MarshalJSON() ([]byte, error) {
+	type row ProductExportRow
+	return json.Marshal(struct {
+		row
+		Price    string `json:"price"`
+		Currency string `json:"currency"`
+	}{row(r), r.Price.String(), r.Price.Currency})
+}
+
+// ProductSuggestQuery asks for autocomplete suggestions for what a shopper
+// has typed so far. Limit defaults to 5.
+type ProductSuggestQuery struct {
+	Q     string `form:"q" binding:"required,max=100"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=20"`
+}
+
+// ProductSuggestion is an active product whose name, or the name of its
+// category, resembles the query. Matched tells which of the two did, and
+// Score is the trigram word similarity from 0 to 1.
+type ProductSuggestion struct {
+	ID       uint               `json:"id"`
+	Name     string             `json:"name"`
+	Slug     string             `json:"slug"`
+	Category SuggestionCategory `json:"category"`
+	Matched  string             `json:"matched"`
+	Score    float64            `json:"score"`
+}
+
+type SuggestionCategory struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
 }
 
+// ProductReportResponse summarizes the catalog. Prices in different
+// currencies cannot be averaged together, so AveragePrices has one average
+// per currency, rounded to its minor unit.
 type ProductReportResponse struct {
-	TotalProducts int                 `json:"total_products"`
-	TotalStock    int64               `json:"total_stock"`
-	AveragePrice  float64             `json:"average_price"`
-	Products      []ProductReportItem `json:"products"`
+	TotalProducts    int                 `json:"total_products"`
+	TotalStock       int64               `json:"total_stock"`
+	AveragePrices    []money.Money       `json:"average_prices"`
+	StockByWarehouse []WarehouseStock    `json:"stock_by_warehouse"`
+	Products         []ProductReportItem `json:"products"`
 }
 
+// ProductReportItem reports the stock of a product summed over the product
+// and its variants.
 type ProductReportItem struct {
-	ID            uint   `json:"id"`
-	Name          string `json:"name"`
-	CategoryName  string `json:"category_name"`
-	Price         int    `json:"price"`
-	StockQuantity int    `json:"stock_quantity"`
+	ID            uint        `json:"id"`
+	Name          string      `json:"name"`
+	CategoryName  string      `json:"category_name"`
+	Price         money.Money `json:"price" gorm:"embedded"`
+	StockQuantity int         `json:"stock_quantity"`
+	VariantCount  int         `json:"variant_count"`
 }
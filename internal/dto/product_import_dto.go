@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusRunning   = "running"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// ImportProductRow is one record of an import file. The category can be
+// given either by ID or by name. The price is a decimal amount, as a JSON
+// number or string, in Currency or the default currency when it is empty.
+// StockQuantity is nil when the row leaves it out.
+type ImportProductRow struct {
+	Row           int         `json:"-"`
+	ID            *uint       `json:"id"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description"`
+	Price         json.Number `json:"price"`
+	Currency      string      `json:"currency"`
+	StockQuantity *int        `json:"stock_quantity"`
+	IsActive      bool        `json:"is_active"`
+	CategoryID    *uint       `json:"category_id"`
+	Category      string      `json:"category"`
+
+	// ParseErrors holds the cells that could not be decoded.
+	ParseErrors map[string]ParseError `json:"-"`
+}
+
+// ParseError is why a cell of an import file could not be decoded: Key
+// names the validation message that describes it, which takes Params.
+type ParseError struct {
+	Key    string
+	Params []string
+}
+
+type ImportOptions struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson"`
+	DryRun bool   `form:"dry_run"`
+}
+
+type ImportRowError struct {
+	Row    int               `json:"row"`
+	Errors map[string]string `json:"errors"`
+}
+
+type ImportJob struct {
+	ID            string           `json:"id"`
+	Status        string           `json:"status"`
+	DryRun        bool             `json:"dry_run"`
+	TotalRows     int              `json:"total_rows"`
+	ProcessedRows int              `json:"processed_rows"`
+	Created       int              `json:"created"`
+	Updated       int              `json:"updated"`
+	Failed        int              `json:"failed"`
+	Errors        []ImportRowError `json:"errors"`
+	Message       string           `json:"message,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	FinishedAt    *time.Time       `json:"finished_at"`
+}
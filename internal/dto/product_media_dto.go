@@ -0,0 +1,11 @@
+package dto
+
+// MediaUploadRequest holds the form fields sent with an uploaded image.
+type MediaUploadRequest struct {
+	IsPrimary bool `form:"is_primary"`
+}
+
+// MediaOrderRequest lists every media of a product in the order to show them.
+type MediaOrderRequest struct {
+	MediaIDs []uint `json:"media_ids" binding:"required,min=1,dive,gt=0"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+// VariantRequest is the full representation of a variant accepted by POST.
+// StockQuantity is its opening stock.
+type VariantRequest struct {
+	SKU           string            `json:"sku" binding:"required,max=64"`
+	Options       map[string]string `json:"options" binding:"omitempty,dive,keys,required,endkeys,required"`
+	Price         MoneyRequest      `json:"price"`
+	StockQuantity int               `json:"stock_quantity" binding:"gte=0"`
+	IsActive      bool              `json:"is_active"`
+}
+
+// ReplaceVariantRequest is the full representation of a variant accepted by
+// PUT. StockQuantity may not be set: stock only changes through stock
+// movements.
+type ReplaceVariantRequest struct {
+	SKU           string            `json:"sku" binding:"required,max=64"`
+	Options       map[string]string `json:"options" binding:"omitempty,dive,keys,required,endkeys,required"`
+	Price         MoneyRequest      `json:"price"`
+	StockQuantity *int              `json:"stock_quantity" binding:"ledger"`
+	IsActive      bool              `json:"is_active"`
+}
@@ -0,0 +1,92 @@
+package dto
+
+import (
+	"slices"
+	"test-elabram/internal/filter"
+)
+
+// FieldsQuery asks a read endpoint for a sparse response: Fields lists the
+// fields to return and Include the related records to embed. Include is a
+// pointer so that an empty include= can ask for no relations at all.
+type FieldsQuery struct {
+	Fields  string  `form:"fields"`
+	Include *string `form:"include"`
+}
+
+// Projection is a checked FieldsQuery. The zero Projection is a full
+// response: every field and every relation.
+type Projection struct {
+	Fields  []string
+	Include []string
+	sparse  bool
+}
+
+// ProductFields are the fields of a product a client may ask for.
+var ProductFields = []string{
+	"id", "name", "slug", "description", "price", "effective_price", "list_price", "stock_quantity", "available_quantity",
+	"reorder_threshold", "is_active", "category_id", "attributes", "created_at", "updated_at", "name_highlight", "description_highlight",
+}
+
+// ProductIncludes are the relations a product response may embed.
+var ProductIncludes = []string{"category", "media", "tags", "variants"}
+
+// CategoryFields are the fields of a category a client may ask for.
+// Categories have no relations to include.
+var CategoryFields = []string{"id", "name", "slug", "description", "parent_id", "path", "reorder_threshold", "created_at", "updated_at"}
+
+// Projection checks the query against the allowed fields and relations. It
+// returns the errors by parameter when it names others.
+func (q FieldsQuery) Projection(fields []string, includes []string) (Projection, map[string]*filter.Error) {
+	errs := map[string]*filter.Error{}
+	var proj Projection
+	if q.Fields != "" {
+		parsed, err := filter.ParseList(q.Fields, fields)
+		if err != nil {
+			errs["fields"] = err
+		}
+		proj.Fields = parsed
+		proj.sparse = true
+	}
+	if q.Include != nil {
+		parsed, err := filter.ParseList(*q.Include, includes)
+		if err != nil {
+			errs["include"] = err
+		}
+		proj.Include = parsed
+		proj.sparse = true
+	}
+	return proj, errs
+}
+
+// Sparse reports whether the response is limited to some fields or
+// relations.
+func (p Projection) Sparse() bool {
+	return p.sparse
+}
+
+// HasField reports whether the response carries the field. The ID is always
+// returned.
+func (p Projection) HasField(field string) bool {
+	return len(p.Fields) == 0 || field == "id" || slices.Contains(p.Fields, field)
+}
+
+// Includes reports whether the response embeds the relation. Without an
+// include parameter, a response limited to some fields embeds nothing.
+func (p Projection) Includes(relation string) bool {
+	return !p.sparse || slices.Contains(p.Include, relation)
+}
+
+// Columns returns the fields to select, always with required, or nil to
+// select every column.
+func (p Projection) Columns(required ...string) []string {
+	if len(p.Fields) == 0 {
+		return nil
+	}
+	columns := slices.Clone(required)
+	for _, field := range p.Fields {
+		if !slices.Contains(columns, field) {
+			columns = append(columns, field)
+		}
+	}
+	return columns
+}
@@ -0,0 +1,30 @@
+package dto
+
+// Reservation statuses. Only active holds that have not expired count
+// against the available quantity.
+const (
+	ReservationActive    = "active"
+	ReservationConfirmed = "confirmed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+// ReservationRequest holds stock for a checkout. TTLSeconds defaults to ten
+// minutes.
+type ReservationRequest struct {
+	Reference  string                   `json:"reference" binding:"max=255"`
+	TTLSeconds int                      `json:"ttl_seconds" binding:"omitempty,min=60,max=3600"`
+	Items      []ReservationItemRequest `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+type ReservationItemRequest struct {
+	ProductID uint  `json:"product_id" binding:"required,gt=0"`
+	VariantID *uint `json:"variant_id" binding:"omitempty,gt=0"`
+	Quantity  int   `json:"quantity" binding:"required,gt=0"`
+}
+
+// ConfirmReservationRequest chooses the warehouse the confirmed sale is
+// taken from, the default one when WarehouseID is not set.
+type ConfirmReservationRequest struct {
+	WarehouseID *uint `form:"warehouse_id" binding:"omitempty,gt=0"`
+}
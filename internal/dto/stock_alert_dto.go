@@ -0,0 +1,14 @@
+package dto
+
+// StockStatus is the total stock of a product, including its variants,
+// against its effective reorder threshold: its own, or that of the nearest
+// category up the tree that sets one. ReorderThreshold is nil when neither
+// does.
+type StockStatus struct {
+	ProductID        uint   `json:"product_id"`
+	Name             string `json:"name"`
+	Slug             string `json:"slug"`
+	CategoryID       uint   `json:"category_id"`
+	StockQuantity    int    `json:"stock_quantity"`
+	ReorderThreshold *int   `json:"reorder_threshold"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+const (
+	MovementReceipt    = "receipt"
+	MovementSale       = "sale"
+	MovementAdjustment = "adjustment"
+	MovementReturn     = "return"
+	MovementTransfer   = "transfer"
+)
+
+// StockMovementRequest records a change of stock in one warehouse, the
+// default one when WarehouseID is not set. Quantity is the number of units
+// received, sold or returned, and the signed change for adjustments.
+// Transfers are made with a StockTransferRequest.
+type StockMovementRequest struct {
+	Type        string `json:"type" binding:"required,oneof=receipt sale adjustment return"`
+	Quantity    int    `json:"quantity" binding:"required"`
+	VariantID   *uint  `json:"variant_id" binding:"omitempty,gt=0"`
+	WarehouseID *uint  `json:"warehouse_id" binding:"omitempty,gt=0"`
+	Reason      string `json:"reason" binding:"required,max=255"`
+}
+
+type StockMovementQuery struct {
+	Type        string `form:"type" binding:"omitempty,oneof=receipt sale adjustment return transfer"`
+	VariantID   *uint  `form:"variant_id" binding:"omitempty,gt=0"`
+	WarehouseID *uint  `form:"warehouse_id" binding:"omitempty,gt=0"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+// TagRequest is the full representation of a tag accepted by POST and PUT.
+// The slug is derived from the name.
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
@@ -0,0 +1,22 @@
+package dto
+
+// TranslationRequest is the text of a product or category in one locale.
+type TranslationRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description" binding:"required"`
+}
+
+// MissingTranslationQuery limits the missing translations to one locale;
+// all translated locales are listed when Locale is empty.
+type MissingTranslationQuery struct {
+	Locale string `form:"locale"`
+}
+
+// MissingTranslation is a product or category without a translation into
+// Locale, with its text in the default locale.
+type MissingTranslation struct {
+	ID     uint   `json:"id"`
+	Name   string `json:"name"`
+	Slug   string `json:"slug"`
+	Locale string `json:"locale"`
+}
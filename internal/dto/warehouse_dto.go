@@ -0,0 +1,28 @@
+package dto
+
+// WarehouseRequest is the full representation of a warehouse accepted by
+// POST and PUT. Making a warehouse the default unsets the previous one.
+type WarehouseRequest struct {
+	Code      string `json:"code" binding:"required,max=32"`
+	Name      string `json:"name" binding:"required"`
+	IsDefault bool   `json:"is_default"`
+}
+
+// StockTransferRequest moves stock of a product, or of one of its variants,
+// between two warehouses.
+type StockTransferRequest struct {
+	ProductID       uint   `json:"product_id" binding:"required,gt=0"`
+	VariantID       *uint  `json:"variant_id" binding:"omitempty,gt=0"`
+	FromWarehouseID uint   `json:"from_warehouse_id" binding:"required,gt=0"`
+	ToWarehouseID   uint   `json:"to_warehouse_id" binding:"required,gt=0"`
+	Quantity        int    `json:"quantity" binding:"required,gt=0"`
+	Reason          string `json:"reason" binding:"required,max=255"`
+}
+
+// WarehouseStock is the total stock held in one warehouse.
+type WarehouseStock struct {
+	WarehouseID uint   `json:"warehouse_id"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Quantity    int64  `json:"quantity"`
+}
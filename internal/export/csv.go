@@ -0,0 +1,28 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"test-elabram/internal/dto"
+)
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return nil, err
+	}
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) WriteRow(row dto.ProductExportRow) error {
+	return c.w.Write(record(row))
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
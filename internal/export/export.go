@@ -0,0 +1,73 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"test-elabram/internal/dto"
+	"time"
+)
+
+// Writer encodes catalog rows one at a time. Close must be called to flush
+// the trailing parts of the format; it does not close the underlying writer.
+type Writer interface {
+	WriteRow(row dto.ProductExportRow) error
+	Close() error
+}
+
+var header = []string{
+	"id",
+	"name",
+	"description",
+	"price",
+	"currency",
+	"stock_quantity",
+	"is_active",
+	"category_id",
+	"category_name",
+	"created_at",
+	"updated_at",
+}
+
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case "csv":
+		return newCSVWriter(w)
+	case "ndjson":
+		return newNDJSONWriter(w), nil
+	case "xlsx":
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func ContentType(format string) string {
+	switch format {
+	case "csv":
+		return "text/csv; charset=utf-8"
+	case "ndjson":
+		return "application/x-ndjson"
+	case "xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// record returns the row as strings in header order.
+func record(row dto.ProductExportRow) []string {
+	return []string{
+		strconv.FormatUint(uint64(row.ID), 10),
+		row.Name,
+		row.Description,
+		row.Price.String(),
+		row.Price.Currency,
+		strconv.Itoa(row.StockQuantity),
+		strconv.FormatBool(row.IsActive),
+		strconv.FormatUint(uint64(row.CategoryID), 10),
+		row.CategoryName,
+		row.CreatedAt.Format(time.RFC3339),
+		row.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,23 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"test-elabram/internal/dto"
+)
+
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{enc: json.NewEncoder(w)}
+}
+
+func (n *ndjsonWriter) WriteRow(row dto.ProductExportRow) error {
+	return n.enc.Encode(row)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
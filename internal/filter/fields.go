@@ -0,0 +1,31 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ParseList parses a comma-separated list of names, such as the fields or
+// relations a client asks for, that must be among allowed. Empty entries and
+// repeats are dropped.
+func ParseList(expr string, allowed []string) ([]string, *Error) {
+	names := []string{}
+	for _, part := range strings.Split(expr, ",") {
+		name := strings.TrimSpace(part)
+		if name == "" || slices.Contains(names, name) {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			quoted := strconv.Quote(name)
+			if len(allowed) == 0 {
+				return nil, &Error{Expr: expr, Message: "unknown name " + quoted + "; none are allowed", Key: "list_none", Params: []string{quoted}}
+			}
+			list := strings.Join(allowed, ", ")
+			return nil, &Error{Expr: expr, Message: fmt.Sprintf("unknown name %s; allowed: %s", quoted, list), Key: "list_name", Params: []string{quoted, list}}
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
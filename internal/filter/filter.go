@@ -0,0 +1,232 @@
+// Package filter parses the filter and sort expressions of list endpoints
+// against a whitelist of fields. Values are converted to the field's type
+// while parsing, so the conditions can be bound as query parameters and a
+// field name never reaches SQL unless the schema allows it.
+//
+// A filter expression is field:op:value, e.g. is_active:eq:true,
+// created_at:gte:2026-01-01 or category_id:in:1,2,3. A sort expression is a
+// comma-separated list of fields, each descending when prefixed with "-",
+// e.g. -price,name.
+package filter
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Type is the type of a filterable field, which decides its operators and
+// how its values are parsed.
+type Type int
+
+const (
+	Bool Type = iota
+	Int
+	Number
+	String
+	Time
+)
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	Eq       Op = "eq"
+	Ne       Op = "ne"
+	Gt       Op = "gt"
+	Gte      Op = "gte"
+	Lt       Op = "lt"
+	Lte      Op = "lte"
+	In       Op = "in"
+	Nin      Op = "nin"
+	Contains Op = "contains"
+)
+
+// MaxValues is the most values an in or nin list may hold.
+const MaxValues = 100
+
+var typeOps = map[Type][]Op{
+	Bool:   {Eq, Ne},
+	Int:    {Eq, Ne, Gt, Gte, Lt, Lte, In, Nin},
+	Number: {Eq, Ne, Gt, Gte, Lt, Lte, In, Nin},
+	String: {Eq, Ne, In, Nin, Contains},
+	Time:   {Gt, Gte, Lt, Lte},
+}
+
+// Schema maps the fields that may be filtered on to their types.
+type Schema map[string]Type
+
+// Condition is a parsed filter expression. Values holds one value, or the
+// list of an in or nin condition, converted to bool, int64, float64, string
+// or time.Time.
+type Condition struct {
+	Field  string
+	Op     Op
+	Values []interface{}
+}
+
+// SortKey is one field of a sort expression.
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// Error describes an expression that cannot be parsed. Message is in
+// English; Key names the message for translation, which takes Args.
+type Error struct {
+	Expr    string
+	Message string
+	Key     string
+	Params  []string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%q: %s", e.Expr, e.Message)
+}
+
+// Args returns the parameters of the message named by Key: the quoted
+// expression, then Params.
+func (e *Error) Args() []string {
+	return append([]string{strconv.Quote(e.Expr)}, e.Params...)
+}
+
+// Fields returns the names of the fields of the schema, sorted.
+func (s Schema) Fields() []string {
+	fields := make([]string, 0, len(s))
+	for field := range s {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+// Parse parses filter expressions. All of them must hold for a row to match.
+func (s Schema) Parse(exprs []string) ([]Condition, error) {
+	conds := make([]Condition, 0, len(exprs))
+	for _, expr := range exprs {
+		cond, err := s.parse(expr)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+func (s Schema) parse(expr string) (Condition, error) {
+	parts := strings.SplitN(expr, ":", 3)
+	if len(parts) != 3 {
+		return Condition{}, &Error{Expr: expr, Message: "must be field:op:value", Key: "expression_format"}
+	}
+	field, op, value := parts[0], Op(parts[1]), parts[2]
+
+	typ, ok := s[field]
+	if !ok {
+		allowed := strings.Join(s.Fields(), ", ")
+		return Condition{}, &Error{Expr: expr, Message: "unknown field; allowed: " + allowed, Key: "expression_field", Params: []string{allowed}}
+	}
+	if !slices.Contains(typeOps[typ], op) {
+		allowed := joinOps(typeOps[typ])
+		return Condition{}, &Error{
+			Expr:    expr,
+			Message: fmt.Sprintf("%s does not support %q; allowed: %s", field, op, allowed),
+			Key:     "expression_op",
+			Params:  []string{field, strconv.Quote(string(op)), allowed},
+		}
+	}
+
+	raw := []string{value}
+	if op == In || op == Nin {
+		raw = strings.Split(value, ",")
+		if len(raw) > MaxValues {
+			return Condition{}, &Error{Expr: expr, Message: fmt.Sprintf("at most %d values", MaxValues), Key: "expression_values", Params: []string{strconv.Itoa(MaxValues)}}
+		}
+	}
+	cond := Condition{Field: field, Op: op, Values: make([]interface{}, 0, len(raw))}
+	for _, v := range raw {
+		parsed, err := parseValue(typ, v)
+		if err != nil {
+			err.Expr = expr
+			return Condition{}, err
+		}
+		cond.Values = append(cond.Values, parsed)
+	}
+	return cond, nil
+}
+
+// parseValue parses a value of the type. Its error lacks the expression.
+func parseValue(typ Type, v string) (interface{}, *Error) {
+	switch typ {
+	case Bool:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, valueError(v, "is not true or false", "expression_bool")
+		}
+		return b, nil
+	case Int:
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, valueError(v, "is not an integer", "expression_int")
+		}
+		return n, nil
+	case Number:
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+			return nil, valueError(v, "is not a number", "expression_number")
+		}
+		return n, nil
+	case Time:
+		// A date is midnight UTC of that day.
+		if t, err := time.Parse(time.DateOnly, v); err == nil {
+			return t, nil
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, valueError(v, "is not a date (2006-01-02) or RFC 3339 time", "expression_time")
+		}
+		return t, nil
+	default:
+		return v, nil
+	}
+}
+
+func valueError(v string, problem string, key string) *Error {
+	quoted := strconv.Quote(v)
+	return &Error{Message: quoted + " " + problem, Key: key, Params: []string{quoted}}
+}
+
+func joinOps(ops []Op) string {
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = string(op)
+	}
+	return strings.Join(names, ", ")
+}
+
+// ParseSort parses a sort expression whose fields must be among allowed.
+func ParseSort(expr string, allowed []string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, part := range strings.Split(expr, ",") {
+		key := SortKey{Field: strings.TrimSpace(part)}
+		if rest, ok := strings.CutPrefix(key.Field, "-"); ok {
+			key.Field, key.Desc = rest, true
+		}
+		if !slices.Contains(allowed, key.Field) {
+			list := strings.Join(allowed, ", ")
+			return nil, &Error{
+				Expr:    expr,
+				Message: fmt.Sprintf("cannot sort by %q; allowed: %s", key.Field, list),
+				Key:     "sort_field",
+				Params:  []string{strconv.Quote(key.Field), list},
+			}
+		}
+		if slices.ContainsFunc(keys, func(k SortKey) bool { return k.Field == key.Field }) {
+			return nil, &Error{Expr: expr, Message: key.Field + " is listed twice", Key: "sort_repeated", Params: []string{key.Field}}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
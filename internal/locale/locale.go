@@ -0,0 +1,60 @@
+// Package locale picks the language product and category content is shown
+// in.
+package locale
+
+import (
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+// Default is the locale of the names and descriptions stored on products
+// and categories themselves. Other locales are stored as translations.
+const Default = "id"
+
+// Supported are the locales content may be shown in, Default first.
+var Supported = []string{Default, "en"}
+
+var matcher = language.NewMatcher([]language.Tag{language.Indonesian, language.English})
+
+// Match returns the supported locale for a language tag such as "en" or
+// "en-GB", and false when none fits.
+func Match(tag string) (string, bool) {
+	t, err := language.Parse(tag)
+	if err != nil {
+		return "", false
+	}
+	_, index, confidence := matcher.Match(t)
+	if confidence == language.No {
+		return "", false
+	}
+	return Supported[index], true
+}
+
+// Negotiate returns the supported locale that best fits an Accept-Language
+// header, or Default when none does.
+func Negotiate(acceptLanguage string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return Supported[index]
+}
+
+// Chain is the fallback chain of a locale: the locales whose translations
+// are tried in order, ending with Default, whose text is always there.
+func Chain(locale string) []string {
+	if locale == Default {
+		return []string{Default}
+	}
+	return []string{locale, Default}
+}
+
+// Translations are the locales content is translated into.
+func Translations() []string {
+	return slices.DeleteFunc(slices.Clone(Supported), func(l string) bool { return l == Default })
+}
@@ -0,0 +1,158 @@
+// Package media checks uploaded images and makes their thumbnails. It only
+// uses the standard library decoders, so JPEG, PNG and GIF are accepted; a
+// GIF keeps its animation but its thumbnail is the first frame.
+package media
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"test-elabram/internal/domain"
+)
+
+const (
+	// MaxSize is the largest file accepted, in bytes.
+	MaxSize = 10 << 20
+	// MaxPixels bounds width × height, so a small file that decodes into a
+	// huge image is refused before it is decoded.
+	MaxPixels = 40_000_000
+	// ThumbnailSize is the longest side of a thumbnail, in pixels.
+	ThumbnailSize = 320
+)
+
+type format struct {
+	ext    string
+	decode func(io.Reader) (image.Image, error)
+	config func(io.Reader) (image.Config, error)
+}
+
+// formats are keyed by the content type http.DetectContentType reports.
+var formats = map[string]format{
+	"image/jpeg": {ext: "jpg", decode: jpeg.Decode, config: jpeg.DecodeConfig},
+	"image/png":  {ext: "png", decode: png.Decode, config: png.DecodeConfig},
+	"image/gif":  {ext: "gif", decode: gif.Decode, config: gif.DecodeConfig},
+}
+
+// Image is a checked upload and its thumbnail.
+type Image struct {
+	Data          []byte
+	ContentType   string
+	Ext           string
+	Width         int
+	Height        int
+	Thumbnail     []byte
+	ThumbnailType string
+	ThumbnailExt  string
+}
+
+// Process reads an upload, sniffs its type from its content rather than
+// trusting its name or headers, and makes its thumbnail. It returns
+// domain.ErrMediaTooLarge for files over MaxSize or MaxPixels and
+// domain.ErrMediaUnsupported for anything that is not a JPEG, PNG or GIF
+// image.
+func Process(r io.Reader) (*Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxSize {
+		return nil, domain.ErrMediaTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	f, ok := formats[contentType]
+	if !ok {
+		return nil, domain.ErrMediaUnsupported
+	}
+	cfg, err := f.config(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.ErrMediaUnsupported
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return nil, domain.ErrMediaUnsupported
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return nil, domain.ErrMediaTooLarge
+	}
+	src, err := f.decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.ErrMediaUnsupported
+	}
+
+	img := &Image{
+		Data:        data,
+		ContentType: contentType,
+		Ext:         f.ext,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+	}
+	if err := img.encodeThumbnail(Thumbnail(src, ThumbnailSize)); err != nil {
+		return nil, err
+	}
+	return img, nil
+}
+
+// encodeThumbnail stores an opaque thumbnail as JPEG and one with
+// transparency as PNG.
+func (img *Image) encodeThumbnail(thumb *image.RGBA) error {
+	var buf bytes.Buffer
+	if thumb.Opaque() {
+		if err := jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 85}); err != nil {
+			return err
+		}
+		img.ThumbnailType, img.ThumbnailExt = "image/jpeg", "jpg"
+	} else {
+		if err := png.Encode(&buf, thumb); err != nil {
+			return err
+		}
+		img.ThumbnailType, img.ThumbnailExt = "image/png", "png"
+	}
+	img.Thumbnail = buf.Bytes()
+	return nil
+}
+
+// Thumbnail scales src down so its longest side is at most size, averaging
+// each block of source pixels into one thumbnail pixel. A smaller image is
+// copied at its own size.
+func Thumbnail(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0, y1 := b.Min.Y+y*h/th, b.Min.Y+(y+1)*h/th
+		for x := 0; x < tw; x++ {
+			x0, x1 := b.Min.X+x*w/tw, b.Min.X+(x+1)*w/tw
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, bl, a = r+uint64(cr), g+uint64(cg), bl+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			// RGBA returns 16-bit premultiplied values, which is what
+			// color.RGBA holds in 8 bits.
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
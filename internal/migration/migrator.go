@@ -0,0 +1,224 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// downDir holds the rollback scripts. Atlas only reads the top level of the
+// migration directory, so keeping them in a subdirectory leaves atlas.sum
+// untouched.
+const downDir = "down"
+
+type Migration struct {
+	Version     string
+	Description string
+	Name        string
+	AppliedAt   *time.Time
+}
+
+// schemaMigration is the bookkeeping row written for every applied migration.
+type schemaMigration struct {
+	Version     string `gorm:"primarykey"`
+	Description string `gorm:"not null"`
+	AppliedAt   time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// A Step finishes a migration in Go, in its transaction after its SQL, for
+// data that SQL cannot derive the way the application does.
+type Step func(tx *gorm.DB) error
+
+type Migrator struct {
+	db    *gorm.DB
+	dir   fs.FS
+	steps map[string]Step
+}
+
+// NewMigrator returns a migrator of the files in dir. steps are run after
+// the migrations of their versions.
+func NewMigrator(db *gorm.DB, dir fs.FS, steps map[string]Step) *Migrator {
+	return &Migrator{
+		db:    db,
+		dir:   dir,
+		steps: steps,
+	}
+}
+
+// Status lists every migration file together with the time it was applied,
+// if it has been.
+func (m *Migrator) Status(ctx context.Context) ([]Migration, error) {
+	migrations, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range migrations {
+		if at, ok := applied[migrations[i].Version]; ok {
+			migrations[i].AppliedAt = &at
+		}
+	}
+	return migrations, nil
+}
+
+// Up applies every pending migration in version order, each in its own
+// transaction together with its step, if any.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	migrations, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, mig := range migrations {
+		if mig.AppliedAt != nil {
+			continue
+		}
+		stmts, err := fs.ReadFile(m.dir, mig.Name)
+		if err != nil {
+			return done, err
+		}
+		err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(string(stmts)).Error; err != nil {
+				return err
+			}
+			if step, ok := m.steps[mig.Version]; ok {
+				if err := step(tx); err != nil {
+					return err
+				}
+			}
+			return tx.Create(&schemaMigration{
+				Version:     mig.Version,
+				Description: mig.Description,
+				AppliedAt:   time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("apply %s: %w", mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Down rolls back the latest applied migrations using the scripts in the
+// down directory.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	migrations, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		mig := migrations[i]
+		if mig.AppliedAt == nil {
+			continue
+		}
+		stmts, err := fs.ReadFile(m.dir, path.Join(downDir, mig.Name))
+		if err != nil {
+			return done, fmt.Errorf("no rollback script for %s: %w", mig.Name, err)
+		}
+		err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(string(stmts)).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, "version = ?", mig.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("roll back %s: %w", mig.Name, err)
+		}
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+// Baseline marks every migration up to and including version as applied
+// without running it. It is meant for databases that were migrated with the
+// Atlas CLI before this command existed.
+func (m *Migrator) Baseline(ctx context.Context, version string) error {
+	migrations, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, mig := range migrations {
+		if mig.Version == version {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("unknown migration version %q", version)
+	}
+
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, mig := range migrations {
+			if mig.Version > version || mig.AppliedAt != nil {
+				continue
+			}
+			if err := tx.Create(&schemaMigration{
+				Version:     mig.Version,
+				Description: mig.Description,
+				AppliedAt:   time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[string]time.Time, error) {
+	if err := m.db.WithContext(ctx).AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := m.db.WithContext(ctx).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row.AppliedAt
+	}
+	return applied, nil
+}
+
+// load reads the migration files, named <version>_<description>.sql, in
+// version order.
+func (m *Migrator) load() ([]Migration, error) {
+	names, err := fs.Glob(m.dir, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	migrations := make([]Migration, 0, len(names))
+	for _, name := range names {
+		version, description, ok := strings.Cut(strings.TrimSuffix(name, ".sql"), "_")
+		if !ok || version == "" {
+			return nil, errors.New("invalid migration file name: " + name)
+		}
+		migrations = append(migrations, Migration{
+			Version:     version,
+			Description: description,
+			Name:        name,
+		})
+	}
+	return migrations, nil
+}
@@ -0,0 +1,143 @@
+// Package money represents prices exactly, as an integer amount of the minor
+// unit of an ISO 4217 currency.
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The currencies the store sells in.
+const (
+	IDR = "IDR"
+	USD = "USD"
+
+	// DefaultCurrency is the currency of prices that do not name one.
+	DefaultCurrency = IDR
+)
+
+// exponents holds the number of decimal places of each currency. Rupiah
+// prices are kept in whole rupiah, as sen are no longer in circulation.
+var exponents = map[string]int{
+	IDR: 0,
+	USD: 2,
+}
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrInvalidAmount   = errors.New("invalid amount")
+	ErrPrecision       = errors.New("too many decimal places for the currency")
+)
+
+// Money is an amount in the minor unit of its currency, e.g. 1999 for
+// USD 19.99. It is stored in two columns, price and currency; embed it with
+// gorm:"embedded", and an embeddedPrefix for a second amount in the same
+// table. In JSON it is an object with the amount as a decimal string:
+//
+//	{"amount": "19.99", "currency": "USD"}
+type Money struct {
+	Amount   int64  `gorm:"column:price;not null"`
+	Currency string `gorm:"column:currency;not null;default:IDR"`
+}
+
+// New returns amount minor units of currency.
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Currencies returns the supported currency codes in order.
+func Currencies() []string {
+	codes := make([]string, 0, len(exponents))
+	for code := range exponents {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Exponent returns the number of decimal places of currency.
+func Exponent(currency string) (int, bool) {
+	exp, ok := exponents[currency]
+	return exp, ok
+}
+
+// Parse reads a non-negative decimal amount, such as "19.99", in currency. It
+// fails rather than rounds when the amount has more decimal places than the
+// currency.
+func Parse(amount, currency string) (Money, error) {
+	exp, ok := exponents[currency]
+	if !ok {
+		return Money{}, fmt.Errorf("%w %q", ErrUnknownCurrency, currency)
+	}
+
+	whole, frac, hasPoint := strings.Cut(amount, ".")
+	if whole == "" || !isDigits(whole) || (hasPoint && (frac == "" || !isDigits(frac))) {
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidAmount, amount)
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > exp {
+		return Money{}, fmt.Errorf("%w: %s has %d", ErrPrecision, currency, exp)
+	}
+
+	minor, err := strconv.ParseInt(whole+frac+strings.Repeat("0", exp-len(frac)), 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w %q", ErrInvalidAmount, amount)
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// String formats the amount as a decimal with the currency's decimal places,
+// without the currency.
+func (m Money) String() string {
+	exp := exponents[m.Currency]
+	if exp == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+
+	sign, abs := "", m.Amount
+	if abs < 0 {
+		sign, abs = "-", -abs
+	}
+	unit := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/unit, exp, abs%unit)
+}
+
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{Amount: m.String(), Currency: m.Currency})
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v moneyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	amount, negative := strings.CutPrefix(v.Amount, "-")
+	parsed, err := Parse(amount, v.Currency)
+	if err != nil {
+		return err
+	}
+	if negative {
+		parsed.Amount = -parsed.Amount
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,26 @@
+// Package notifier delivers low-stock alerts.
+package notifier
+
+import (
+	"context"
+	"log"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+)
+
+type logNotifier struct{}
+
+// NewLogNotifier returns a notifier that writes alerts to the standard log.
+func NewLogNotifier() domain.StockAlertNotifier {
+	return logNotifier{}
+}
+
+func (logNotifier) Name() string {
+	return "log"
+}
+
+func (logNotifier) Notify(ctx context.Context, status dto.StockStatus) error {
+	log.Printf("[ALERT] Low stock: product %d %q has %d units left (reorder threshold %d)",
+		status.ProductID, status.Name, status.StockQuantity, *status.ReorderThreshold)
+	return nil
+}
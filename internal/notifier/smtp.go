@@ -0,0 +1,60 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+)
+
+type smtpNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+// NewSMTPNotifier returns a notifier that e-mails alerts through the SMTP
+// server at addr. Without a username no authentication is attempted, which
+// suits local test servers such as Mailpit.
+func NewSMTPNotifier(addr, username, password, from string, to []string) domain.StockAlertNotifier {
+	n := &smtpNotifier{
+		addr: addr,
+		from: from,
+		to:   to,
+	}
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		n.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return n
+}
+
+func (n *smtpNotifier) Name() string {
+	return "smtp"
+}
+
+// Notify sends the alert. net/smtp does not take a context, so a slow
+// server holds up the evaluator until it answers.
+func (n *smtpNotifier) Notify(ctx context.Context, status dto.StockStatus) error {
+	subject := fmt.Sprintf("Low stock: %s", status.Name)
+	body := fmt.Sprintf("Product %d %q has %d units left, at or below its reorder threshold of %d.\r\n",
+		status.ProductID, status.Name, status.StockQuantity, *status.ReorderThreshold)
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	// Encoding the subject also keeps line breaks in product names out of
+	// the headers.
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	return smtp.SendMail(n.addr, n.auth, n.from, n.to, []byte(msg.String()))
+}
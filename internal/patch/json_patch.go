@@ -0,0 +1,303 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"test-elabram/internal/domain"
+)
+
+const JSONPatchContentType = "application/json-patch+json"
+
+type Operation struct {
+	Op    string           `json:"op"`
+	Path  string           `json:"path"`
+	From  string           `json:"from,omitempty"`
+	Value *json.RawMessage `json:"value,omitempty"`
+}
+
+// UnmarshalJSON sets Value whenever the operation has the value member,
+// including a null one, which the pointer alone would read as missing.
+func (op *Operation) UnmarshalJSON(data []byte) error {
+	type operation Operation
+	if err := json.Unmarshal(data, (*operation)(op)); err != nil {
+		return err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	if value, ok := members["value"]; ok {
+		op.Value = &value
+	}
+	return nil
+}
+
+// JSONPatch is a JSON Patch document (RFC 6902). Operations are applied in
+// order and the whole patch fails if any of them does.
+type JSONPatch []Operation
+
+func ParseJSONPatch(data []byte) (JSONPatch, error) {
+	var p JSONPatch
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+	}
+	for i, op := range p {
+		switch op.Op {
+		case "add", "replace", "test":
+			if op.Value == nil {
+				return nil, fmt.Errorf("%w: operation %d (%s) requires a value", domain.ErrInvalidPatch, i, op.Op)
+			}
+		case "move", "copy":
+			if _, err := parsePointer(op.From); err != nil {
+				return nil, fmt.Errorf("%w: operation %d: %v", domain.ErrInvalidPatch, i, err)
+			}
+		case "remove":
+		default:
+			return nil, fmt.Errorf("%w: operation %d has unknown op %q", domain.ErrInvalidPatch, i, op.Op)
+		}
+		if _, err := parsePointer(op.Path); err != nil {
+			return nil, fmt.Errorf("%w: operation %d: %v", domain.ErrInvalidPatch, i, err)
+		}
+	}
+	return p, nil
+}
+
+func (p JSONPatch) Apply(doc []byte) ([]byte, error) {
+	var root interface{}
+	if err := json.Unmarshal(doc, &root); err != nil {
+		return nil, err
+	}
+
+	for i, op := range p {
+		var err error
+		root, err = op.apply(root)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d (%s %s): %w", i, op.Op, op.Path, err)
+		}
+	}
+	return json.Marshal(root)
+}
+
+func (op Operation) apply(root interface{}) (interface{}, error) {
+	path, _ := parsePointer(op.Path)
+
+	switch op.Op {
+	case "add":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	case "remove":
+		root, _, err := remove(root, path)
+		return root, err
+	case "replace":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		if root, _, err = remove(root, path); err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	case "move":
+		from, _ := parsePointer(op.From)
+		if isPrefix(from, path) && len(from) < len(path) {
+			return nil, fmt.Errorf("%w: cannot move a value into one of its children", domain.ErrInvalidPatch)
+		}
+		root, value, err := remove(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	case "copy":
+		from, _ := parsePointer(op.From)
+		value, err := get(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, deepCopy(value))
+	case "test":
+		want, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+		got, err := get(root, path)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			return nil, domain.ErrPatchTestFailed
+		}
+		return root, nil
+	}
+	return nil, fmt.Errorf("%w: unknown op %q", domain.ErrInvalidPatch, op.Op)
+}
+
+func (op Operation) value() (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(*op.Value, &v); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+	}
+	return v, nil
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into unescaped tokens.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func get(node interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			v, ok := n[token]
+			if !ok {
+				return nil, fmt.Errorf("%w: path not found", domain.ErrInvalidPatch)
+			}
+			node = v
+		case []interface{}:
+			i, err := arrayIndex(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, fmt.Errorf("%w: path not found", domain.ErrInvalidPatch)
+		}
+	}
+	return node, nil
+}
+
+// add sets value at path and returns the possibly replaced root.
+func add(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[last] = value
+		return root, nil
+	case []interface{}:
+		i := len(p)
+		if last != "-" {
+			if i, err = arrayIndex(last, len(p)); err != nil {
+				return nil, err
+			}
+		}
+		grown := append(p[:i:i], append([]interface{}{value}, p[i:]...)...)
+		return setParent(root, path[:len(path)-1], grown)
+	}
+	return nil, fmt.Errorf("%w: path not found", domain.ErrInvalidPatch)
+}
+
+// remove deletes the value at path and returns the root and the removed
+// value.
+func remove(root interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, root, nil
+	}
+	parent, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	last := path[len(path)-1]
+
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		v, ok := p[last]
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: path not found", domain.ErrInvalidPatch)
+		}
+		delete(p, last)
+		return root, v, nil
+	case []interface{}:
+		i, err := arrayIndex(last, len(p)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		v := p[i]
+		shrunk := append(p[:i:i], p[i+1:]...)
+		root, err = setParent(root, path[:len(path)-1], shrunk)
+		return root, v, err
+	}
+	return nil, nil, fmt.Errorf("%w: path not found", domain.ErrInvalidPatch)
+}
+
+// setParent stores a resized array back into its container.
+func setParent(root interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	container, err := get(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch c := container.(type) {
+	case map[string]interface{}:
+		c[last] = value
+	case []interface{}:
+		i, err := arrayIndex(last, len(c)-1)
+		if err != nil {
+			return nil, err
+		}
+		c[i] = value
+	}
+	return root, nil
+}
+
+func arrayIndex(token string, maxIndex int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > maxIndex || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("%w: invalid array index %q", domain.ErrInvalidPatch, token)
+	}
+	return i, nil
+}
+
+func isPrefix(prefix, path []string) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[k] = deepCopy(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i, val := range t {
+			s[i] = deepCopy(val)
+		}
+		return s
+	default:
+		return v
+	}
+}
@@ -0,0 +1,44 @@
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+	"test-elabram/internal/domain"
+)
+
+const MergePatchContentType = "application/merge-patch+json"
+
+// MergePatch is a JSON Merge Patch document (RFC 7386).
+type MergePatch []byte
+
+func (p MergePatch) Apply(doc []byte) ([]byte, error) {
+	var patch interface{}
+	if err := json.Unmarshal(p, &patch); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidPatch, err)
+	}
+	var target interface{}
+	if err := json.Unmarshal(doc, &target); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(target, patch))
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergePatch(targetObj[key], value)
+	}
+	return targetObj
+}
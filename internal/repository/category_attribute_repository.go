@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type categoryAttributeRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryAttributeRepository(db *gorm.DB) domain.CategoryAttributeRepository {
+	return &categoryAttributeRepository{
+		db: db,
+	}
+}
+
+// GetEffective returns the attributes of the category and of its ancestors,
+// whose paths are prefixes of its path, nearest first. Of several
+// definitions of a name only the nearest is kept. It returns
+// domain.ErrCategoryNotFound when the category does not exist.
+func (r *categoryAttributeRepository) GetEffective(ctx context.Context, categoryID uint) ([]domain.CategoryAttribute, error) {
+	db := r.db.WithContext(ctx)
+	if err := checkCategory(db, categoryID); err != nil {
+		return nil, err
+	}
+	return effectiveAttributes(db, categoryID)
+}
+
+// effectiveAttributes returns the attributes that apply to the products of
+// an existing category, as GetEffective does.
+func effectiveAttributes(db *gorm.DB, categoryID uint) ([]domain.CategoryAttribute, error) {
+	var all []domain.CategoryAttribute
+	err := db.Joins("JOIN categories c ON c.id = category_attributes.category_id").
+		Where("c.path <> '' AND (SELECT path FROM categories WHERE id = ?) LIKE c.path || '%'", categoryID).
+		Order("length(c.path) DESC, category_attributes.name").
+		Find(&all).Error
+	if err != nil {
+		return nil, err
+	}
+
+	attributes := []domain.CategoryAttribute{}
+	seen := map[string]bool{}
+	for _, attribute := range all {
+		if !seen[attribute.Name] {
+			seen[attribute.Name] = true
+			attributes = append(attributes, attribute)
+		}
+	}
+	return attributes, nil
+}
+
+func (r *categoryAttributeRepository) GetByID(ctx context.Context, categoryID int, id int) (*domain.CategoryAttribute, error) {
+	var attribute domain.CategoryAttribute
+	err := r.db.WithContext(ctx).Where("category_id = ?", categoryID).First(&attribute, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrAttributeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attribute, nil
+}
+
+func (r *categoryAttributeRepository) Types(ctx context.Context) (map[string]string, error) {
+	var rows []struct {
+		Name string
+		Type string
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.CategoryAttribute{}).Distinct("name", "type").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	types := make(map[string]string, len(rows))
+	for _, row := range rows {
+		types[row.Name] = row.Type
+	}
+	return types, nil
+}
+
+// Create inserts the attribute unless its name is already used with another
+// type. An advisory lock on the name keeps two categories from defining it
+// with different types at the same time.
+func (r *categoryAttributeRepository) Create(ctx context.Context, attribute *domain.CategoryAttribute) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkCategory(tx, attribute.CategoryID); err != nil {
+			return err
+		}
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "category_attributes:"+attribute.Name).Error; err != nil {
+			return err
+		}
+		var conflicts int64
+		err := tx.Model(&domain.CategoryAttribute{}).Where("name = ? AND type <> ?", attribute.Name, attribute.Type).Count(&conflicts).Error
+		if err != nil {
+			return err
+		}
+		if conflicts > 0 {
+			return domain.ErrAttributeTypeConflict
+		}
+		return translateError(tx.Create(attribute).Error)
+	})
+}
+
+// Edit saves the options and required flag of the attribute.
+func (r *categoryAttributeRepository) Edit(ctx context.Context, attribute *domain.CategoryAttribute) error {
+	return r.db.WithContext(ctx).Model(attribute).Select("Options", "Required", "UpdatedAt").Updates(attribute).Error
+}
+
+// Delete removes the attribute unless a product of the category or of one
+// of its descendants holds a value for it.
+func (r *categoryAttributeRepository) Delete(ctx context.Context, categoryID int, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var attribute domain.CategoryAttribute
+		err := tx.Where("category_id = ?", categoryID).First(&attribute, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrAttributeNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var inUse int64
+		err = tx.Model(&domain.Product{}).
+			Where("products.category_id IN (SELECT id FROM categories WHERE path LIKE (SELECT path FROM categories WHERE id = ?) || '%')", categoryID).
+			Where("products.attributes -> ? IS NOT NULL", attribute.Name).
+			Limit(1).Count(&inUse).Error
+		if err != nil {
+			return err
+		}
+		if inUse > 0 {
+			return domain.ErrAttributeInUse
+		}
+		return tx.Delete(&attribute).Error
+	})
+}
+
+func checkCategory(db *gorm.DB, categoryID uint) error {
+	var count int64
+	if err := db.Model(&domain.Category{}).Where("id = ?", categoryID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrCategoryNotFound
+	}
+	return nil
+}
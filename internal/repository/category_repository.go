@@ -2,11 +2,18 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"test-elabram/internal/domain"
 
 	"gorm.io/gorm"
 )
 
+// categoryTreeLock is the advisory lock key that serializes changes to the
+// category paths, so concurrent moves cannot build a cycle.
+const categoryTreeLock = 7301
+
 type categoryRepository struct {
 	db *gorm.DB
 }
@@ -17,29 +24,207 @@ func NewCategoryRepository(db *gorm.DB) domain.CategoryRepository {
 	}
 }
 
-func (r *categoryRepository) GetAll(ctx context.Context) ([]domain.Category, error) {
+// GetAll lists the categories in tree order, with only the given columns
+// when there are any.
+func (r *categoryRepository) GetAll(ctx context.Context, columns ...string) ([]domain.Category, error) {
 	var categories []domain.Category
-	err := r.db.WithContext(ctx).Find(&categories).Error
+	err := r.db.WithContext(ctx).Scopes(withColumns(columns)).Order("path").Find(&categories).Error
 	return categories, err
 }
 
-func (r *categoryRepository) GetByID(ctx context.Context, id int) (*domain.Category, error) {
+func (r *categoryRepository) GetByID(ctx context.Context, id int, columns ...string) (*domain.Category, error) {
+	var category domain.Category
+	err := r.db.WithContext(ctx).Scopes(withColumns(columns)).First(&category, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetBySlug finds the category by its current slug or, after a rename, by a
+// slug it used before.
+func (r *categoryRepository) GetBySlug(ctx context.Context, slug string, columns ...string) (*domain.Category, error) {
 	var category domain.Category
-	err := r.db.WithContext(ctx).First(&category, id).Error
+	db := r.db.WithContext(ctx)
+	err := db.Scopes(withColumns(columns)).Where("slug = ?", slug).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = db.Scopes(withColumns(columns)).Where("id = (?)", slugRedirectTarget(db, domain.SlugEntityCategory, slug)).First(&category).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrSlugNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &category, nil
 }
 
+// withColumns selects only the given columns, or every column when there are
+// none.
+func withColumns(columns []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(columns) == 0 {
+			return db
+		}
+		return db.Select(columns)
+	}
+}
+
+func (r *categoryRepository) GetByIDs(ctx context.Context, ids []uint) ([]domain.Category, error) {
+	var categories []domain.Category
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("path").Find(&categories).Error
+	return categories, err
+}
+
+// GetSubtree returns the category with the given path and all of its
+// descendants, parents before children.
+func (r *categoryRepository) GetSubtree(ctx context.Context, path string) ([]domain.Category, error) {
+	var categories []domain.Category
+	err := r.db.WithContext(ctx).Where("path LIKE ?", path+"%").Order("path").Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) HasChildren(ctx context.Context, id int) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Category{}).Where("parent_id = ?", id).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
 func (r *categoryRepository) Create(ctx context.Context, category *domain.Category) error {
-	return r.db.WithContext(ctx).Create(category).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		parentPath := "/"
+		if category.ParentID != nil {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", categoryTreeLock).Error; err != nil {
+				return err
+			}
+			var parent domain.Category
+			if err := tx.First(&parent, *category.ParentID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return domain.ErrParentCategoryNotFound
+				}
+				return err
+			}
+			parentPath = parent.Path
+		}
+
+		s, err := uniqueSlug(tx, "categories", domain.SlugEntityCategory, category.Name, 0)
+		if err != nil {
+			return err
+		}
+		category.Slug = s
+		if err := tx.Create(category).Error; err != nil {
+			return translateError(err)
+		}
+		category.Path = fmt.Sprintf("%s%d/", parentPath, category.ID)
+		return tx.Model(category).Update("path", category.Path).Error
+	})
 }
 
+// Edit saves the editable columns of the category. A rename gives it a new
+// slug and keeps the old one resolvable. Its place in the tree is left to
+// Move, so a concurrent move is kept and read back into category.
 func (r *categoryRepository) Edit(ctx context.Context, category *domain.Category) error {
-	return r.db.WithContext(ctx).Save(category).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		s, err := renameSlug(tx, "categories", domain.SlugEntityCategory, category.ID, category.Name)
+		if err != nil {
+			return err
+		}
+		category.Slug = s
+		result := tx.Model(category).Select("Name", "Slug", "Description", "ReorderThreshold", "UpdatedAt").Updates(category)
+		if result.Error != nil {
+			return translateError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrCategoryNotFound
+		}
+		return tx.Select("parent_id", "path").Take(category).Error
+	})
+}
+
+// Move re-parents the category and rewrites the paths of its whole subtree.
+// A nil parentID moves it to the root.
+func (r *categoryRepository) Move(ctx context.Context, id int, parentID *uint) (*domain.Category, error) {
+	var category domain.Category
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", categoryTreeLock).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&category, id).Error; err != nil {
+			return err
+		}
+
+		newPath := fmt.Sprintf("/%d/", category.ID)
+		if parentID != nil {
+			var parent domain.Category
+			if err := tx.First(&parent, *parentID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return domain.ErrParentCategoryNotFound
+				}
+				return err
+			}
+			if strings.HasPrefix(parent.Path, category.Path) {
+				return domain.ErrCategoryCycle
+			}
+			newPath = fmt.Sprintf("%s%d/", parent.Path, category.ID)
+		}
+
+		oldPath := category.Path
+		err := tx.Exec("UPDATE categories SET path = ? || substr(path, ?) WHERE path LIKE ?",
+			newPath, len(oldPath)+1, oldPath+"%").Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&category).Update("parent_id", parentID).Error; err != nil {
+			return err
+		}
+		if err := checkSubtreeAttributes(tx, newPath); err != nil {
+			return err
+		}
+		category.ParentID = parentID
+		category.Path = newPath
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// checkSubtreeAttributes checks the attribute values of the products of the
+// categories under path against the attributes that apply to them once the
+// tree has changed. It returns domain.ErrAttributesMismatch when a value is
+// no longer allowed or a required attribute is missing.
+func checkSubtreeAttributes(tx *gorm.DB, path string) error {
+	var ids []uint
+	if err := tx.Model(&domain.Category{}).Where("path LIKE ?", path+"%").Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	schemas := make(map[uint][]domain.CategoryAttribute, len(ids))
+	for _, id := range ids {
+		schema, err := effectiveAttributes(tx, id)
+		if err != nil {
+			return err
+		}
+		schemas[id] = schema
+	}
+
+	var products []domain.Product
+	return tx.Select("id", "category_id", "attributes").Where("category_id IN ?", ids).
+		FindInBatches(&products, 500, func(batch *gorm.DB, _ int) error {
+			for _, product := range products {
+				if len(domain.CheckAttributes(schemas[product.CategoryID], product.Attributes)) > 0 {
+					return domain.ErrAttributesMismatch
+				}
+			}
+			return nil
+		}).Error
 }
 
 func (r *categoryRepository) Delete(ctx context.Context, id int) error {
-	return r.db.WithContext(ctx).Delete(&domain.Category{}, id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteSlugHistory(tx, domain.SlugEntityCategory, id); err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Category{}, id).Error
+	})
 }
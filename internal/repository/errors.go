@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"errors"
+	"test-elabram/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// translateError maps driver errors that callers handle to domain errors.
+// It relies on gorm's TranslateError option.
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return domain.ErrConflict
+	}
+	return err
+}
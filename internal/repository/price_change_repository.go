@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/money"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type priceChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceChangeRepository(db *gorm.DB) domain.PriceChangeRepository {
+	return &priceChangeRepository{
+		db: db,
+	}
+}
+
+// priceChangeEffectiveTo is the start of the product's next price change.
+const priceChangeEffectiveTo = "LEAD(price_changes.effective_from) OVER (PARTITION BY price_changes.product_id ORDER BY price_changes.effective_from, price_changes.id)"
+
+// productEffectivePrice is the price of the product's latest change that is
+// in effect, which can differ from its price column while a due scheduled
+// change waits for the scheduler.
+const productEffectivePrice = "COALESCE((SELECT pc.price FROM price_changes pc WHERE pc.product_id = products.id AND pc.effective_from <= now() " +
+	"ORDER BY pc.effective_from DESC, pc.id DESC LIMIT 1), products.price)"
+
+// GetByProductID returns the price history of a product, latest effective
+// first, including changes scheduled for later.
+func (r *priceChangeRepository) GetByProductID(ctx context.Context, productID int, pq dto.PaginationQuery) ([]domain.PriceChange, int64, error) {
+	db := r.db.WithContext(ctx)
+	var count int64
+	if err := db.Model(&domain.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	if count == 0 {
+		return nil, 0, domain.ErrProductNotFound
+	}
+
+	var total int64
+	if err := db.Model(&domain.PriceChange{}).Where("product_id = ?", productID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	changes := []domain.PriceChange{}
+	err := db.Select("price_changes.*, "+priceChangeEffectiveTo+" AS effective_to").
+		Where("product_id = ?", productID).
+		Order("effective_from DESC, id DESC").
+		Limit(pq.Limit).
+		Offset((pq.Page - 1) * pq.Limit).
+		Find(&changes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return changes, total, nil
+}
+
+// Schedule records a future price change, which must be in the product's
+// currency.
+func (r *priceChangeRepository) Schedule(ctx context.Context, change *domain.PriceChange) error {
+	db := r.db.WithContext(ctx)
+	if err := checkProductCurrency(db, change.ProductID, change.Price.Currency); err != nil {
+		return err
+	}
+	if change.Actor == "" {
+		change.Actor = domain.ActorFromContext(ctx)
+	}
+	return db.Omit("Product").Create(change).Error
+}
+
+// Cancel deletes a scheduled price change that has not taken effect yet.
+func (r *priceChangeRepository) Cancel(ctx context.Context, productID int, changeID int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var change domain.PriceChange
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ?", productID).
+			First(&change, changeID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrPriceChangeNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if change.AppliedAt != nil || !change.EffectiveFrom.After(time.Now()) {
+			return domain.ErrPriceChangeApplied
+		}
+		return tx.Delete(&change).Error
+	})
+}
+
+// ApplyDue writes the prices of the scheduled changes that took effect by now
+// to their products and returns the IDs of the products. Each product gets
+// the price of its latest change in effect, so a later direct edit is not
+// overwritten by an older scheduled change. The products are locked first,
+// which makes the update see edits that were in flight.
+func (r *priceChangeRepository) ApplyDue(ctx context.Context, now time.Time) ([]uint, error) {
+	var productIDs []uint
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productIDs = nil
+		due := tx.Model(&domain.PriceChange{}).Select("product_id").Where("applied_at IS NULL AND effective_from <= ?", now)
+		err := tx.Raw("SELECT id FROM products WHERE id IN (?) ORDER BY id FOR UPDATE", due).Scan(&productIDs).Error
+		if err != nil || len(productIDs) == 0 {
+			return err
+		}
+
+		err = tx.Exec(`UPDATE products SET price = latest.price, updated_at = ?
+			FROM (SELECT DISTINCT ON (product_id) product_id, price FROM price_changes
+				WHERE product_id IN ? AND effective_from <= ? ORDER BY product_id, effective_from DESC, id DESC) latest
+			WHERE products.id = latest.product_id`,
+			now, productIDs, now).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&domain.PriceChange{}).
+			Where("product_id IN ? AND applied_at IS NULL AND effective_from <= ?", productIDs, now).
+			Update("applied_at", now).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return productIDs, nil
+}
+
+// NextScheduled returns when the earliest pending change takes effect, or
+// nil when none is pending.
+func (r *priceChangeRepository) NextScheduled(ctx context.Context) (*time.Time, error) {
+	var next *time.Time
+	err := r.db.WithContext(ctx).Model(&domain.PriceChange{}).
+		Select("MIN(effective_from)").
+		Where("applied_at IS NULL").
+		Row().Scan(&next)
+	return next, err
+}
+
+// checkProductCurrency returns domain.ErrCurrencyMismatch when the product is
+// not priced in currency, or domain.ErrProductNotFound when it does not
+// exist.
+func checkProductCurrency(db *gorm.DB, productID uint, currency string) error {
+	var currencies []string
+	if err := db.Model(&domain.Product{}).Where("id = ?", productID).Pluck("currency", &currencies).Error; err != nil {
+		return err
+	}
+	if len(currencies) == 0 {
+		return domain.ErrProductNotFound
+	}
+	if currencies[0] != currency {
+		return domain.ErrCurrencyMismatch
+	}
+	return nil
+}
+
+// recordPriceChange adds a change of the product's price to the history,
+// applied at once.
+func recordPriceChange(tx *gorm.DB, productID uint, price money.Money, reason string) error {
+	now := time.Now()
+	return tx.Omit("Product").Create(&domain.PriceChange{
+		ProductID:     productID,
+		Price:         price,
+		EffectiveFrom: now,
+		AppliedAt:     &now,
+		Reason:        reason,
+		Actor:         domain.ActorFromContext(tx.Statement.Context),
+	}).Error
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type priceListRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceListRepository(db *gorm.DB) domain.PriceListRepository {
+	return &priceListRepository{
+		db: db,
+	}
+}
+
+// productCurrencyExponent is the number of decimal places of the product's
+// currency.
+var productCurrencyExponent = func() string {
+	var b strings.Builder
+	b.WriteString("CASE products.currency")
+	for _, code := range money.Currencies() {
+		exp, _ := money.Exponent(code)
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", code, exp)
+	}
+	b.WriteString(" END")
+	return b.String()
+}()
+
+// joinListPrice joins each product with its price in the price list as
+// list_price.price: its override in the list, its effective price when the
+// list is in the product's currency, or else its effective price converted
+// with the exchange rate table and rounded once to the minor unit of the
+// list's currency. The price is NULL when no rate converts the product's
+// currency.
+func joinListPrice(db *gorm.DB, priceList *domain.PriceList) *gorm.DB {
+	exp, _ := money.Exponent(priceList.Currency)
+	return db.Joins("LEFT JOIN LATERAL (SELECT COALESCE("+
+		"(SELECT pli.price FROM price_list_items pli WHERE pli.price_list_id = ? AND pli.product_id = products.id), "+
+		"CASE WHEN products.currency = ? THEN "+productEffectivePrice+" ELSE "+
+		"(SELECT ROUND("+productEffectivePrice+" * er.rate * power(10::numeric, ? - "+productCurrencyExponent+"))::bigint "+
+		"FROM exchange_rates er WHERE er.base_currency = products.currency AND er.quote_currency = ?) END) AS price) list_price ON true",
+		priceList.ID, priceList.Currency, exp, priceList.Currency)
+}
+
+func (r *priceListRepository) GetAll(ctx context.Context) ([]domain.PriceList, error) {
+	priceLists := []domain.PriceList{}
+	err := r.db.WithContext(ctx).Order("code").Find(&priceLists).Error
+	return priceLists, err
+}
+
+func (r *priceListRepository) GetByID(ctx context.Context, id int) (*domain.PriceList, error) {
+	var priceList domain.PriceList
+	err := r.db.WithContext(ctx).First(&priceList, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrPriceListNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &priceList, nil
+}
+
+func (r *priceListRepository) GetByCode(ctx context.Context, code string) (*domain.PriceList, error) {
+	var priceList domain.PriceList
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&priceList).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrPriceListNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &priceList, nil
+}
+
+func (r *priceListRepository) Create(ctx context.Context, priceList *domain.PriceList) error {
+	return translateError(r.db.WithContext(ctx).Create(priceList).Error)
+}
+
+// Delete removes the price list with its overrides.
+func (r *priceListRepository) Delete(ctx context.Context, id int) error {
+	result := r.db.WithContext(ctx).Delete(&domain.PriceList{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrPriceListNotFound
+	}
+	return nil
+}
+
+// GetPrices lists the overrides of a price list by product.
+func (r *priceListRepository) GetPrices(ctx context.Context, priceListID int, pq dto.PaginationQuery) ([]domain.PriceListItem, int64, error) {
+	if _, err := r.GetByID(ctx, priceListID); err != nil {
+		return nil, 0, err
+	}
+
+	query := r.db.WithContext(ctx).Model(&domain.PriceListItem{}).Where("price_list_id = ?", priceListID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	items := []domain.PriceListItem{}
+	offset := (pq.Page - 1) * pq.Limit
+	err := query.Order("product_id").Offset(offset).Limit(pq.Limit).Find(&items).Error
+	return items, total, err
+}
+
+// SetPrice creates or replaces the override of a product in a price list.
+// The price must be in the list's currency.
+func (r *priceListRepository) SetPrice(ctx context.Context, item *domain.PriceListItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var priceList domain.PriceList
+		if err := tx.First(&priceList, item.PriceListID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrPriceListNotFound
+			}
+			return err
+		}
+		if item.Price.Currency != priceList.Currency {
+			return domain.ErrPriceListCurrency
+		}
+
+		var count int64
+		if err := tx.Model(&domain.Product{}).Where("id = ?", item.ProductID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return domain.ErrProductNotFound
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "price_list_id"}, {Name: "product_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"price", "currency", "updated_at"}),
+		}).Create(item).Error
+	})
+}
+
+// DeletePrice removes the override of a product, so its price in the list
+// falls back to conversion.
+func (r *priceListRepository) DeletePrice(ctx context.Context, priceListID int, productID int) error {
+	result := r.db.WithContext(ctx).Where("price_list_id = ? AND product_id = ?", priceListID, productID).Delete(&domain.PriceListItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.GetByID(ctx, priceListID); err != nil {
+			return err
+		}
+		return domain.ErrPriceOverrideNotFound
+	}
+	return nil
+}
+
+func (r *priceListRepository) GetExchangeRates(ctx context.Context) ([]domain.ExchangeRate, error) {
+	rates := []domain.ExchangeRate{}
+	err := r.db.WithContext(ctx).Order("base_currency, quote_currency").Find(&rates).Error
+	return rates, err
+}
+
+// SetExchangeRate creates or replaces the rate of a currency pair.
+func (r *priceListRepository) SetExchangeRate(ctx context.Context, rate *domain.ExchangeRate) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).Create(rate).Error
+}
+
+func (r *priceListRepository) DeleteExchangeRate(ctx context.Context, base string, quote string) error {
+	result := r.db.WithContext(ctx).Where("base_currency = ? AND quote_currency = ?", base, quote).Delete(&domain.ExchangeRate{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrExchangeRateNotFound
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+)
+
+// CountByCategory counts the filtered products per category, most first.
+// Categories are named in the locale of ctx.
+func (r *productRepository) CountByCategory(ctx context.Context, params dto.ProductFilterParams, priceList *domain.PriceList) ([]dto.CategoryFacet, error) {
+	facets := []dto.CategoryFacet{}
+	err := applyProductFilters(r.db.WithContext(ctx).Model(&domain.Product{}), params, priceList).
+		Select("products.category_id, COALESCE(ct.name, categories.name) AS name, COUNT(*) AS count").
+		Joins("JOIN categories ON categories.id = products.category_id").
+		Joins("LEFT JOIN category_translations ct ON ct.category_id = categories.id AND ct.locale = ?", domain.LocaleFromContext(ctx)).
+		Group("products.category_id, COALESCE(ct.name, categories.name)").
+		Order("count DESC, name").
+		Scan(&facets).Error
+	return facets, err
+}
+
+// CountByPriceBucket counts the filtered products per price bucket, split at
+// bounds in minor units of currency. Without a price list, the product's own
+// price is counted, so only products priced in currency are. With one, the
+// list price is, which is already in the list's currency; products without
+// one are left out. The result has one count more than bounds.
+func (r *productRepository) CountByPriceBucket(ctx context.Context, params dto.ProductFilterParams, priceList *domain.PriceList, currency string, bounds []int64) ([]int64, error) {
+	query := applyProductFilters(r.db.WithContext(ctx).Model(&domain.Product{}), params, priceList)
+	priceExpr := "list_price.price"
+	if priceList == nil {
+		priceExpr = "products.price"
+		query = query.Where("products.currency = ?", currency)
+	} else {
+		query = query.Where("list_price.price IS NOT NULL")
+	}
+
+	var bucket strings.Builder
+	args := make([]interface{}, 0, len(bounds))
+	bucket.WriteString("CASE")
+	for i, bound := range bounds {
+		bucket.WriteString(" WHEN " + priceExpr + " < ? THEN " + strconv.Itoa(i))
+		args = append(args, bound)
+	}
+	bucket.WriteString(" ELSE " + strconv.Itoa(len(bounds)) + " END")
+
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	err := query.Select(bucket.String()+" AS bucket, COUNT(*) AS count", args...).Group("bucket").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]int64, len(bounds)+1)
+	for _, row := range rows {
+		counts[row.Bucket] = row.Count
+	}
+	return counts, nil
+}
+
+// CountByStock counts the filtered products with stock above zero and
+// without, measured like the stock range.
+func (r *productRepository) CountByStock(ctx context.Context, params dto.ProductFilterParams, priceList *domain.PriceList) (*dto.StockFacet, error) {
+	var facet dto.StockFacet
+	stockExpr, stockArgs := productStockExpr(params)
+	err := applyProductFilters(r.db.WithContext(ctx).Model(&domain.Product{}), params, priceList).
+		Select("COUNT(*) FILTER (WHERE "+stockExpr+" > 0) AS in_stock, COUNT(*) FILTER (WHERE "+stockExpr+" <= 0) AS out_of_stock", append(stockArgs, stockArgs...)...).
+		Scan(&facet).Error
+	if err != nil {
+		return nil, err
+	}
+	return &facet, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"test-elabram/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type productMediaRepository struct {
+	db *gorm.DB
+}
+
+func NewProductMediaRepository(db *gorm.DB) domain.ProductMediaRepository {
+	return &productMediaRepository{
+		db: db,
+	}
+}
+
+// orderMedia keeps preloaded media in display order.
+func orderMedia(db *gorm.DB) *gorm.DB {
+	return db.Order("product_media.position, product_media.id")
+}
+
+// lockProduct locks the product row, so changes to the positions and primary
+// flag of its media are serialized. It returns domain.ErrProductNotFound when
+// the product does not exist.
+func lockProduct(tx *gorm.DB, productID int) error {
+	var product domain.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&product, productID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrProductNotFound
+	}
+	return err
+}
+
+// GetByProductID lists the media of a product, or returns
+// domain.ErrProductNotFound when the product does not exist.
+func (r *productMediaRepository) GetByProductID(ctx context.Context, productID int) ([]domain.ProductMedia, error) {
+	db := r.db.WithContext(ctx)
+	if err := checkProduct(db, uint(productID)); err != nil {
+		return nil, err
+	}
+
+	media := []domain.ProductMedia{}
+	err := db.Scopes(orderMedia).Where("product_id = ?", productID).Find(&media).Error
+	return media, err
+}
+
+func (r *productMediaRepository) GetByID(ctx context.Context, productID int, id int) (*domain.ProductMedia, error) {
+	var media domain.ProductMedia
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).First(&media, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// Create appends the media after the product's others. The first media of a
+// product becomes its primary one whatever IsPrimary says.
+func (r *productMediaRepository) Create(ctx context.Context, media *domain.ProductMedia) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, int(media.ProductID)); err != nil {
+			return err
+		}
+		var stats struct {
+			Count    int64
+			Position int
+		}
+		err := tx.Model(&domain.ProductMedia{}).Select("COUNT(*) AS count, COALESCE(MAX(position) + 1, 0) AS position").
+			Where("product_id = ?", media.ProductID).Scan(&stats).Error
+		if err != nil {
+			return err
+		}
+		media.Position = stats.Position
+		if stats.Count == 0 {
+			media.IsPrimary = true
+		} else if media.IsPrimary {
+			if err := clearPrimary(tx, media.ProductID); err != nil {
+				return err
+			}
+		}
+		return translateError(tx.Create(media).Error)
+	})
+}
+
+// Delete removes the media and returns it, so its files can be removed too.
+// When it was the primary media, the first of the remaining ones takes over.
+func (r *productMediaRepository) Delete(ctx context.Context, productID int, id int) (*domain.ProductMedia, error) {
+	var media domain.ProductMedia
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		result := tx.Clauses(clause.Returning{}).Where("product_id = ?", productID).Delete(&media, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrMediaNotFound
+		}
+		if !media.IsPrimary {
+			return nil
+		}
+		return tx.Model(&domain.ProductMedia{}).
+			Where("id = (?)", tx.Model(&domain.ProductMedia{}).Select("id").Where("product_id = ?", productID).Scopes(orderMedia).Limit(1)).
+			Update("is_primary", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// Reorder sets the positions of the product's media to the order of ids,
+// which must list each of them exactly once.
+func (r *productMediaRepository) Reorder(ctx context.Context, productID int, ids []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		var current []uint
+		if err := tx.Model(&domain.ProductMedia{}).Where("product_id = ?", productID).Pluck("id", &current).Error; err != nil {
+			return err
+		}
+		sorted := slices.Clone(ids)
+		slices.Sort(sorted)
+		slices.Sort(current)
+		if !slices.Equal(sorted, current) {
+			return domain.ErrMediaOrder
+		}
+
+		for position, id := range ids {
+			if err := tx.Model(&domain.ProductMedia{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetPrimary makes the media the product's primary one.
+func (r *productMediaRepository) SetPrimary(ctx context.Context, productID int, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockProduct(tx, productID); err != nil {
+			return err
+		}
+		var media domain.ProductMedia
+		err := tx.Where("product_id = ?", productID).First(&media, id).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrMediaNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if media.IsPrimary {
+			return nil
+		}
+		if err := clearPrimary(tx, media.ProductID); err != nil {
+			return err
+		}
+		return tx.Model(&media).Update("is_primary", true).Error
+	})
+}
+
+func clearPrimary(tx *gorm.DB, productID uint) error {
+	return tx.Model(&domain.ProductMedia{}).Where("product_id = ? AND is_primary", productID).Update("is_primary", false).Error
+}
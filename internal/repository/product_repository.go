@@ -2,11 +2,19 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"test-elabram/internal/domain"
 	"test-elabram/internal/dto"
+	"test-elabram/internal/filter"
+	"test-elabram/internal/locale"
+	"test-elabram/internal/money"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type productRepository struct {
@@ -21,90 +29,496 @@ func NewProductRepository(db *gorm.DB) domain.ProductRepository {
 
 func (r *productRepository) GetAll(ctx context.Context) ([]domain.Product, error) {
 	var products []domain.Product
-	err := r.db.WithContext(ctx).Preload("Category").Find(&products).Error
+	err := r.db.WithContext(ctx).Scopes(withComputedColumns).Preload("Category").Preload("Variants", orderVariants).Preload("Media", orderMedia).Preload("Tags", orderTags).Find(&products).Error
 	return products, err
 }
 
-var allowedSortColumns = map[string]bool{
-	"name":           true,
-	"price":          true,
-	"stock_quantity": true,
-	"created_at":     true,
-	"category_id":    true,
+// orderVariants keeps preloaded variants in creation order and computes
+// their available quantity.
+func orderVariants(db *gorm.DB) *gorm.DB {
+	return db.Select("product_variants.*, " + variantAvailableQuantity + " AS available_quantity").Order("product_variants.id")
 }
 
-func (r *productRepository) GetAllPaginated(ctx context.Context, params dto.ProductFilterParams, pq dto.PaginationQuery) ([]domain.Product, int64, error) {
+// orderTags keeps preloaded tags in name order.
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name, tags.id")
+}
+
+// productTotalStock is the stock of a product including all its variants.
+const productTotalStock = "(products.stock_quantity + COALESCE((SELECT SUM(v.stock_quantity) FROM product_variants v WHERE v.product_id = products.id), 0))"
+
+// productAvailableQuantity is the product's own stock minus the quantity
+// held by active reservations.
+const productAvailableQuantity = "GREATEST(products.stock_quantity - COALESCE((SELECT SUM(ri.quantity) FROM reservation_items ri JOIN reservations rs ON rs.id = ri.reservation_id " +
+	"WHERE ri.product_id = products.id AND ri.variant_id IS NULL AND rs.status = 'active' AND rs.expires_at > now()), 0), 0)"
+
+// productComputedColumns selects the products with their available quantity
+// and effective price.
+const productComputedColumns = "products.*, " + productAvailableQuantity + " AS available_quantity, " + productEffectivePrice + " AS effective_price"
+
+func withComputedColumns(db *gorm.DB) *gorm.DB {
+	return db.Select(productComputedColumns)
+}
+
+// withProjection selects the columns of the fields in proj, with the price
+// in the price list joined by joinListPrice when priceList is set and the
+// relevance and highlights of a search for q when it is set, and preloads
+// the relations proj includes.
+func withProjection(proj dto.Projection, priceList *domain.PriceList, q string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		columns := productColumns(proj)
+		if priceList != nil && proj.HasField("list_price") {
+			columns += ", list_price.price AS list_price"
+		}
+		searchColumns, searchArgs := productSearchColumns(q, domain.LocaleFromContext(db.Statement.Context))
+		db = db.Select(columns+searchColumns, searchArgs...)
+		if proj.Includes("category") {
+			db = db.Preload("Category")
+		}
+		if proj.Includes("variants") {
+			db = db.Preload("Variants", orderVariants)
+		}
+		if proj.Includes("media") {
+			db = db.Preload("Media", orderMedia)
+		}
+		if proj.Includes("tags") {
+			db = db.Preload("Tags", orderTags)
+		}
+		return db
+	}
+}
+
+// productColumns returns the select list of the fields in proj. The ID and
+// slug are always selected, as relations and slug redirects need them, and
+// the category ID when the category is included.
+func productColumns(proj dto.Projection) string {
+	fields := proj.Columns("id", "slug")
+	if fields == nil {
+		return productComputedColumns
+	}
+	if proj.Includes("category") {
+		fields = append(fields, "category_id")
+	}
+	var columns []string
+	add := func(column string) {
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	for _, field := range fields {
+		switch field {
+		case "price":
+			add("products.price")
+			add("products.currency")
+		case "effective_price":
+			add(productEffectivePrice + " AS effective_price")
+			add("products.currency")
+		case "available_quantity":
+			add(productAvailableQuantity + " AS available_quantity")
+		case "list_price", "name_highlight", "description_highlight":
+			// Selected by withProjection with the price list or search.
+		default:
+			add("products." + field)
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// setListPrice gives the list price selected into ListAmount the currency of
+// the price list.
+func setListPrice(product *domain.Product, priceList *domain.PriceList) {
+	if product.ListAmount != nil {
+		price := money.New(*product.ListAmount, priceList.Currency)
+		product.ListPrice = &price
+	}
+}
+
+// GetAllPaginated lists the filtered products. With a price list, each
+// product carries its price in the list, which the price range and sorting
+// by price then apply to. A search gives each product highlighted snippets
+// of its name and description. Only the fields and relations in proj are
+// loaded.
+func (r *productRepository) GetAllPaginated(ctx context.Context, params dto.ProductFilterParams, priceList *domain.PriceList, pq dto.PaginationQuery, proj dto.Projection) ([]domain.Product, int64, error) {
 	var products []domain.Product
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&domain.Product{})
+	query := applyProductFilters(r.db.WithContext(ctx).Model(&domain.Product{}), params, priceList)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query = query.Order(orderBy(productOrder(params, priceList != nil, domain.LocaleFromContext(ctx))))
+
+	offset := (pq.Page - 1) * pq.Limit
+	err := query.Offset(offset).Limit(pq.Limit).Scopes(withProjection(proj, priceList, params.Q)).Find(&products).Error
+	if priceList != nil {
+		for i := range products {
+			setListPrice(&products[i], priceList)
+		}
+	}
+	return products, total, err
+}
+
+// StreamForExport walks the filtered products row by row with the category
+// name joined in, so memory use does not grow with the catalog. Exports are
+// in the products' own text, which names are sorted by.
+func (r *productRepository) StreamForExport(ctx context.Context, params dto.ProductFilterParams, fn func(row dto.ProductExportRow) error) error {
+	columns := "products.id, products.name, products.description, products.price, products.currency, products.stock_quantity, products.is_active, products.category_id, categories.name AS category_name, products.created_at, products.updated_at"
+	var args []interface{}
+	if params.Q != "" {
+		// Selected only to sort by relevance.
+		rank, rankArgs := productSearchRank(params.Q, domain.LocaleFromContext(ctx))
+		columns += ", " + rank + " AS search_rank"
+		args = rankArgs
+	}
+	query := applyProductFilters(r.db.WithContext(ctx).Model(&domain.Product{}), params, nil).
+		Select(columns, args...).
+		Joins("LEFT JOIN categories ON categories.id = products.category_id").
+		Order(orderBy(productOrder(params, false, locale.Default)))
+
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row dto.ProductExportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
 
+// applyProductFilters adds the listing filters to a query on products.
+// Columns are qualified so the query can be joined with other tables. With a
+// price list, the query is joined with the list prices by joinListPrice.
+func applyProductFilters(query *gorm.DB, params dto.ProductFilterParams, priceList *domain.PriceList) *gorm.DB {
+	lang := domain.LocaleFromContext(query.Statement.Context)
+	if params.Q != "" {
+		condition, args := productSearchCondition(params.Q, lang)
+		query = query.Where(condition, args...)
+	}
 	if params.Name != "" {
-		query = query.Where("name ILIKE ?", "%"+params.Name+"%")
+		name, args := localizedProductColumn("name", lang)
+		query = query.Where(name+" ILIKE ?", append(args, "%"+params.Name+"%")...)
 	}
 	if params.CategoryID != nil {
-		query = query.Where("category_id = ?", *params.CategoryID)
+		if params.IncludeDescendants {
+			query = query.Where("products.category_id IN (SELECT id FROM categories WHERE path LIKE (SELECT path FROM categories WHERE id = ?) || '%')", *params.CategoryID)
+		} else {
+			query = query.Where("products.category_id = ?", *params.CategoryID)
+		}
 	}
-	if params.PriceMin != nil {
-		query = query.Where("price >= ?", *params.PriceMin)
+	if params.Currency != "" {
+		query = query.Where("products.currency = ?", params.Currency)
 	}
-	if params.PriceMax != nil {
-		query = query.Where("price <= ?", *params.PriceMax)
+	var listCurrency string
+	if priceList != nil {
+		query = joinListPrice(query, priceList)
+		listCurrency = priceList.Currency
 	}
-	if params.StockMin != nil {
-		query = query.Where("stock_quantity >= ?", *params.StockMin)
+	currency := params.PriceCurrency(listCurrency)
+	priceMin, errMin := priceBound(params.PriceMin, currency)
+	priceMax, errMax := priceBound(params.PriceMax, currency)
+	if err := errors.Join(errMin, errMax); err != nil {
+		_ = query.AddError(err)
+		return query
 	}
-	if params.StockMax != nil {
-		query = query.Where("stock_quantity <= ?", *params.StockMax)
+	hasPriceRange := priceMin != nil || priceMax != nil
+	switch {
+	case priceList != nil:
+		// In a price list, the range applies to the list price, which
+		// products without one never match.
+		if cond, args := rangeCondition("list_price.price", priceMin, priceMax); cond != "" {
+			query = query.Where(cond, args...)
+		}
+	case hasPriceRange:
+		// Prices are only comparable within a currency, so a price range
+		// also limits the listing to its currency. A product matches when
+		// its own price or the price of one of its active variants falls
+		// inside it.
+		cond, args := rangeCondition("products.price", priceMin, priceMax)
+		variantCond, variantArgs := rangeCondition("v.price", priceMin, priceMax)
+		query = query.Where("products.currency = ?", currency).
+			Where("("+cond+") OR EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = products.id AND v.is_active AND "+variantCond+")",
+				append(args, variantArgs...)...)
+	}
+	stockExpr, stockArgs := productStockExpr(params)
+	if cond, bounds := rangeCondition(stockExpr, params.StockMin, params.StockMax); cond != "" {
+		// Each bound is compared with its own copy of the expression.
+		var args []interface{}
+		for _, bound := range bounds {
+			args = append(append(args, stockArgs...), bound)
+		}
+		query = query.Where(cond, args...)
+	}
+	conds, err := params.Conditions()
+	if err != nil {
+		_ = query.AddError(err)
+		return query
+	}
+	for _, cond := range conds {
+		where, args := conditionClause(cond, lang)
+		query = query.Where(where, args...)
 	}
+	for _, tag := range params.Tag {
+		query = query.Where("EXISTS (SELECT 1 FROM product_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.product_id = products.id AND t.slug = ?)", tag)
+	}
+	attrConds, err := params.AttributeConditions()
+	if err != nil {
+		_ = query.AddError(err)
+		return query
+	}
+	for _, cond := range attrConds {
+		clause, args := attributeConditionClause(cond)
+		query = query.Where(clause, args...)
+	}
+	return query
+}
 
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
+// conditionOperators are the SQL of the filter operators.
+var conditionOperators = map[filter.Op]string{
+	filter.Eq:       "= ?",
+	filter.Ne:       "<> ?",
+	filter.Gt:       "> ?",
+	filter.Gte:      ">= ?",
+	filter.Lt:       "< ?",
+	filter.Lte:      "<= ?",
+	filter.In:       "IN ?",
+	filter.Nin:      "NOT IN ?",
+	filter.Contains: "ILIKE ?",
+}
+
+// conditionClause compiles a filter condition on a products column, the
+// name being that in lang and the stock the total stock. The field was
+// checked against dto.ProductFilterFields while parsing, so it can be used as
+// a column name; the values are bound.
+func conditionClause(cond filter.Condition, lang string) (string, []interface{}) {
+	column, args := productColumn(cond.Field, lang)
+	expr := column + " " + conditionOperators[cond.Op]
+	switch cond.Op {
+	case filter.In, filter.Nin:
+		return expr, append(args, cond.Values)
+	case filter.Contains:
+		return expr, append(args, "%"+cond.Values[0].(string)+"%")
+	default:
+		return expr, append(args, cond.Values[0])
 	}
+}
 
-	sortBy := "created_at"
-	if allowedSortColumns[params.SortBy] {
-		sortBy = params.SortBy
+// attributeConditionClause compiles a filter condition on an attribute
+// value. Equality tests use JSONB containment, which the GIN index on
+// products.attributes serves; ne and nin only match products that have the
+// attribute. Ranges only apply to number attributes, whose values are always
+// JSON numbers.
+func attributeConditionClause(cond filter.Condition) (string, []interface{}) {
+	contains := func(value interface{}) interface{} {
+		data, _ := json.Marshal(map[string]interface{}{cond.Field: value})
+		return string(data)
+	}
+	switch cond.Op {
+	case filter.Eq:
+		return "products.attributes @> ?::jsonb", []interface{}{contains(cond.Values[0])}
+	case filter.Ne:
+		return "products.attributes -> ? IS NOT NULL AND NOT products.attributes @> ?::jsonb", []interface{}{cond.Field, contains(cond.Values[0])}
+	case filter.In, filter.Nin:
+		tests := make([]string, len(cond.Values))
+		args := make([]interface{}, len(cond.Values))
+		for i, value := range cond.Values {
+			tests[i] = "products.attributes @> ?::jsonb"
+			args[i] = contains(value)
+		}
+		anyOf := "(" + strings.Join(tests, " OR ") + ")"
+		if cond.Op == filter.Nin {
+			return "products.attributes -> ? IS NOT NULL AND NOT " + anyOf, append([]interface{}{cond.Field}, args...)
+		}
+		return anyOf, args
+	case filter.Contains:
+		return "products.attributes ->> ? ILIKE ?", []interface{}{cond.Field, "%" + cond.Values[0].(string) + "%"}
+	default:
+		return "(products.attributes ->> ?)::numeric " + conditionOperators[cond.Op], []interface{}{cond.Field, cond.Values[0]}
+	}
+}
+
+// productStockExpr is the stock the stock range applies to: the total stock,
+// or the stock held in one warehouse when warehouse_id is given. It takes the
+// warehouse ID in that case and no arguments otherwise.
+func productStockExpr(params dto.ProductFilterParams) (string, []interface{}) {
+	if params.WarehouseID != nil {
+		return "COALESCE((SELECT SUM(sl.quantity) FROM stock_levels sl WHERE sl.product_id = products.id AND sl.warehouse_id = ?), 0)", []interface{}{*params.WarehouseID}
+	}
+	return productTotalStock, nil
+}
+
+// priceBound converts a price filter amount to minor units of currency.
+func priceBound(amount *string, currency string) (*int64, error) {
+	if amount == nil {
+		return nil, nil
+	}
+	price, err := money.Parse(*amount, currency)
+	if err != nil {
+		return nil, err
+	}
+	return &price.Amount, nil
+}
+
+// rangeCondition returns "expr >= ? AND expr <= ?" for the bounds that are
+// set, or an empty condition when neither is.
+func rangeCondition[T int | int64](expr string, min, max *T) (string, []interface{}) {
+	var conds []string
+	var args []interface{}
+	if min != nil {
+		conds = append(conds, expr+" >= ?")
+		args = append(args, *min)
+	}
+	if max != nil {
+		conds = append(conds, expr+" <= ?")
+		args = append(args, *max)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// orderBy is an ORDER BY clause whose SQL takes args.
+func orderBy(sql string, args []interface{}) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{SQL: sql, Vars: args, WithoutParentheses: true}}
+}
+
+// productOrder returns the ORDER BY clause of the listing and its arguments.
+// Sorting by price uses the list price when the query is joined with a price
+// list, sorting by name the name in lang, and sorting by stock the total
+// stock. A search sorts by relevance,
+// selected as search_rank, unless another column is asked for.
+func productOrder(params dto.ProductFilterParams, byListPrice bool, lang string) (string, []interface{}) {
+	if keys, err := params.SortKeys(); err == nil && len(keys) > 0 {
+		if order, args := sortKeysOrder(keys, params.Q != "", byListPrice, lang); order != "" {
+			return order, args
+		}
 	}
 	sortOrder := "desc"
 	if params.SortOrder == "asc" {
 		sortOrder = "asc"
 	}
-	query = query.Order(fmt.Sprintf("%s %s", sortBy, sortOrder))
+	if params.Q != "" && (params.SortBy == "" || params.SortBy == "relevance") {
+		return fmt.Sprintf("search_rank %s, products.id %s", sortOrder, sortOrder), nil
+	}
+	sortBy := "created_at"
+	if params.SortBy != "relevance" && slices.Contains(dto.ProductSortByColumns, params.SortBy) {
+		sortBy = params.SortBy
+	}
+	if sortBy == "price" && byListPrice {
+		return fmt.Sprintf("list_price.price %s NULLS LAST, products.id %s", sortOrder, sortOrder), nil
+	}
+	if sortBy == "price" {
+		// Prices sort within their currency.
+		return fmt.Sprintf("products.currency, products.price %s, products.id %s", sortOrder, sortOrder), nil
+	}
+	column, args := productColumn(sortBy, lang)
+	return fmt.Sprintf("%s %s, products.id %s", column, sortOrder, sortOrder), args
+}
 
-	offset := (pq.Page - 1) * pq.Limit
-	err := query.Offset(offset).Limit(pq.Limit).Preload("Category").Find(&products).Error
-	return products, total, err
+// productColumn is the expression a product field is filtered and sorted by:
+// the name in lang, the total stock including the variants, or the column.
+func productColumn(field, lang string) (string, []interface{}) {
+	switch field {
+	case "name":
+		return localizedProductColumn("name", lang)
+	case "stock_quantity":
+		return productTotalStock, nil
+	default:
+		return "products." + field, nil
+	}
+}
+
+// sortKeysOrder returns the ORDER BY clause of a sort expression, with the
+// product ID last, unless it is sorted by, in the direction of the first key
+// so pages are stable.
+// relevance is skipped without a search, which leaves "" when it is the only
+// key.
+func sortKeysOrder(keys []filter.SortKey, searching bool, byListPrice bool, lang string) (string, []interface{}) {
+	var terms []string
+	var args []interface{}
+	for _, key := range keys {
+		dir := "asc"
+		if key.Desc {
+			dir = "desc"
+		}
+		switch {
+		case key.Field == "relevance" && !searching:
+			continue
+		case key.Field == "relevance":
+			terms = append(terms, "search_rank "+dir)
+		case key.Field == "price" && byListPrice:
+			terms = append(terms, "list_price.price "+dir+" NULLS LAST")
+		case key.Field == "price":
+			terms = append(terms, "products.currency", "products.price "+dir)
+		default:
+			column, columnArgs := productColumn(key.Field, lang)
+			terms = append(terms, column+" "+dir)
+			args = append(args, columnArgs...)
+		}
+	}
+	if len(terms) == 0 {
+		return "", nil
+	}
+	if slices.ContainsFunc(keys, func(k filter.SortKey) bool { return k.Field == "id" }) {
+		return strings.Join(terms, ", "), args
+	}
+	dir := "asc"
+	if keys[0].Desc {
+		dir = "desc"
+	}
+	return strings.Join(terms, ", ") + ", products.id " + dir, args
 }
 
 func (r *productRepository) GetProductReport(ctx context.Context) (*dto.ProductReportResponse, error) {
 	var report dto.ProductReportResponse
 
 	row := r.db.WithContext(ctx).Model(&domain.Product{}).
-		Select("COUNT(*) as total_products, COALESCE(SUM(stock_quantity), 0) as total_stock, COALESCE(AVG(price), 0) as average_price").
+		Select("COUNT(*) as total_products, COALESCE(SUM(stock_quantity), 0) + (SELECT COALESCE(SUM(stock_quantity), 0) FROM product_variants) as total_stock").
 		Row()
-	if err := row.Scan(&report.TotalProducts, &report.TotalStock, &report.AveragePrice); err != nil {
+	if err := row.Scan(&report.TotalProducts, &report.TotalStock); err != nil {
 		return nil, err
 	}
 
-	var products []domain.Product
-	err := r.db.WithContext(ctx).
-		Select("id, name, price, stock_quantity, category_id").
-		Preload("Category").
-		Find(&products).Error
+	// AVG of a bigint is an exact numeric in Postgres, so each average is
+	// only rounded once, to the minor unit of its currency.
+	report.AveragePrices = []money.Money{}
+	err := r.db.WithContext(ctx).Model(&domain.Product{}).
+		Select("ROUND(AVG(price))::bigint AS price, currency").
+		Group("currency").
+		Order("currency").
+		Scan(&report.AveragePrices).Error
 	if err != nil {
 		return nil, err
 	}
 
-	report.Products = make([]dto.ProductReportItem, len(products))
-	for i, p := range products {
-		report.Products[i] = dto.ProductReportItem{
-			ID:            p.ID,
-			Name:          p.Name,
-			CategoryName:  p.Category.Name,
-			Price:         p.Price,
-			StockQuantity: p.StockQuantity,
-		}
+	report.Products = []dto.ProductReportItem{}
+	err = r.db.WithContext(ctx).Model(&domain.Product{}).
+		Select("products.id, products.name, categories.name AS category_name, products.price, products.currency, " +
+			productTotalStock + " AS stock_quantity, " +
+			"(SELECT COUNT(*) FROM product_variants v WHERE v.product_id = products.id) AS variant_count").
+		Joins("LEFT JOIN categories ON categories.id = products.category_id").
+		Order("products.id").
+		Scan(&report.Products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	report.StockByWarehouse = []dto.WarehouseStock{}
+	err = r.db.WithContext(ctx).Model(&domain.Warehouse{}).
+		Select("warehouses.id AS warehouse_id, warehouses.code, warehouses.name, COALESCE(SUM(stock_levels.quantity), 0) AS quantity").
+		Joins("LEFT JOIN stock_levels ON stock_levels.warehouse_id = warehouses.id").
+		Group("warehouses.id").
+		Order("warehouses.id").
+		Scan(&report.StockByWarehouse).Error
+	if err != nil {
+		return nil, err
 	}
 
 	return &report, nil
@@ -112,7 +526,47 @@ func (r *productRepository) GetProductReport(ctx context.Context) (*dto.ProductR
 
 func (r *productRepository) GetByID(ctx context.Context, id int) (*domain.Product, error) {
 	var product domain.Product
-	err := r.db.WithContext(ctx).Preload("Category").First(&product, id).Error
+	err := r.db.WithContext(ctx).Scopes(withComputedColumns).Preload("Category").Preload("Variants", orderVariants).Preload("Media", orderMedia).Preload("Tags", orderTags).First(&product, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetByIDProjected is GetByID loading only the fields and relations in proj,
+// and with the product's price in the price list unless that is nil.
+func (r *productRepository) GetByIDProjected(ctx context.Context, id int, priceList *domain.PriceList, proj dto.Projection) (*domain.Product, error) {
+	var product domain.Product
+	db := r.db.WithContext(ctx)
+	if priceList != nil {
+		db = joinListPrice(db, priceList)
+	}
+	err := db.Scopes(withProjection(proj, priceList, "")).First(&product, id).Error
+	if err != nil {
+		return nil, err
+	}
+	if priceList != nil {
+		setListPrice(&product, priceList)
+	}
+	return &product, nil
+}
+
+// GetBySlug finds the product by its current slug or, after a rename, by a
+// slug it used before. Callers can compare the returned product's slug with
+// the requested one to detect the latter. Only the fields and relations in
+// proj are loaded.
+func (r *productRepository) GetBySlug(ctx context.Context, slug string, proj dto.Projection) (*domain.Product, error) {
+	var product domain.Product
+	db := r.db.WithContext(ctx)
+	err := db.Scopes(withProjection(proj, nil, "")).Where("slug = ?", slug).First(&product).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = db.Scopes(withProjection(proj, nil, "")).
+			Where("id = (?)", slugRedirectTarget(db, domain.SlugEntityProduct, slug)).
+			First(&product).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrSlugNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -120,13 +574,220 @@ func (r *productRepository) GetByID(ctx context.Context, id int) (*domain.Produc
 }
 
 func (r *productRepository) Create(ctx context.Context, product *domain.Product) error {
-	return r.db.WithContext(ctx).Create(product).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createProduct(tx, product)
+	})
 }
 
+// Edit saves the product. A rename gives it a new slug and keeps the old one
+// resolvable. Variants and media are managed separately and are not written;
+// tags are replaced unless nil.
 func (r *productRepository) Edit(ctx context.Context, product *domain.Product) error {
-	return r.db.WithContext(ctx).Save(product).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveProduct(tx, product)
+	})
+}
+
+// createProduct inserts the product with a unique slug and no stock, then
+// adds its opening stock through the ledger and starts its price history.
+func createProduct(tx *gorm.DB, product *domain.Product) error {
+	s, err := uniqueSlug(tx, "products", domain.SlugEntityProduct, product.Name, 0)
+	if err != nil {
+		return err
+	}
+	product.Slug = s
+	opening := product.StockQuantity
+	product.StockQuantity = 0
+	if err := tx.SavePoint("product").Error; err != nil {
+		return err
+	}
+	err = tx.Omit("Variants", "Media", "Tags.*").Create(product).Error
+	product.StockQuantity = opening
+	if err != nil {
+		return productWriteError(tx, product, err)
+	}
+	if err := recordOpeningStock(tx, product.ID, nil, opening); err != nil {
+		return err
+	}
+	if err := recordPriceChange(tx, product.ID, product.Price, "initial price"); err != nil {
+		return err
+	}
+	return scanProductComputedColumns(tx, product)
+}
+
+// productWriteError translates the error of writing the product after the
+// "product" savepoint. A duplicate key is domain.ErrProductNameTaken when
+// another product of the category has the name, which the
+// idx_products_category_name index forbids, and domain.ErrConflict
+// otherwise.
+func productWriteError(tx *gorm.DB, product *domain.Product, err error) error {
+	err = translateError(err)
+	if !errors.Is(err, domain.ErrConflict) {
+		return err
+	}
+	if rbErr := tx.RollbackTo("product").Error; rbErr != nil {
+		return rbErr
+	}
+	var taken bool
+	if qErr := tx.Raw("SELECT EXISTS (SELECT 1 FROM products WHERE category_id = ? AND lower(name) = lower(?) AND id <> ?)", product.CategoryID, product.Name, product.ID).Scan(&taken).Error; qErr != nil {
+		return qErr
+	}
+	if taken {
+		return domain.ErrProductNameTaken
+	}
+	return err
+}
+
+// saveProduct updates every column of the product but its stock quantity,
+// which only changes through stock movements and is read back. A changed
+// price is added to the price history. The currency cannot change, as the
+// prices of the variants and the history are in it.
+func saveProduct(tx *gorm.DB, product *domain.Product) error {
+	s, err := renameSlug(tx, "products", domain.SlugEntityProduct, product.ID, product.Name)
+	if err != nil {
+		return err
+	}
+	product.Slug = s
+
+	// The row is locked, so the price cannot change until the save commits.
+	var current []money.Money
+	if err := tx.Raw("SELECT price, currency FROM products WHERE id = ? FOR UPDATE", product.ID).Scan(&current).Error; err != nil {
+		return err
+	}
+	if len(current) > 0 && current[0].Currency != product.Price.Currency {
+		return domain.ErrCurrencyMismatch
+	}
+	if err := tx.SavePoint("product").Error; err != nil {
+		return err
+	}
+	if err := tx.Omit("Variants", "Media", "Tags", "StockQuantity").Save(product).Error; err != nil {
+		return productWriteError(tx, product, err)
+	}
+	if product.Tags != nil {
+		if err := tx.Model(product).Omit("Tags.*").Association("Tags").Replace(product.Tags); err != nil {
+			return err
+		}
+	}
+	if len(current) > 0 && current[0] != product.Price {
+		if err := recordPriceChange(tx, product.ID, product.Price, "price set directly"); err != nil {
+			return err
+		}
+	}
+	return scanProductComputedColumns(tx, product)
+}
+
+// scanProductComputedColumns reads back the stock of the product and the
+// columns derived from it and its prices.
+func scanProductComputedColumns(tx *gorm.DB, product *domain.Product) error {
+	err := tx.Model(&domain.Product{}).Select("stock_quantity, "+productAvailableQuantity+", "+productEffectivePrice).Where("id = ?", product.ID).
+		Row().Scan(&product.StockQuantity, &product.AvailableQuantity, &product.EffectiveAmount)
+	product.EffectivePrice = money.New(product.EffectiveAmount, product.Price.Currency)
+	return err
+}
+
+// Delete removes the product and returns its media, whose rows go with it
+// but whose files the caller has to remove.
+func (r *productRepository) Delete(ctx context.Context, id int) ([]domain.ProductMedia, error) {
+	var media []domain.ProductMedia
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteSlugHistory(tx, domain.SlugEntityProduct, id); err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Find(&media).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Product{}, id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// existingProductColumns are the columns an import needs of the products it
+// updates.
+var existingProductColumns = []string{"id", "category_id", "attributes", "stock_quantity"}
+
+func (r *productRepository) GetByCategoryAndName(ctx context.Context, categoryID uint, name string) (*domain.Product, error) {
+	var product domain.Product
+	err := r.db.WithContext(ctx).Select(existingProductColumns).
+		Where("category_id = ? AND LOWER(name) = LOWER(?)", categoryID, name).
+		Take(&product).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *productRepository) GetExisting(ctx context.Context, ids []uint) (map[uint]*domain.Product, error) {
+	existing := make(map[uint]*domain.Product, len(ids))
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	var found []domain.Product
+	err := r.db.WithContext(ctx).Select(existingProductColumns).Where("id IN ?", ids).Find(&found).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range found {
+		existing[found[i].ID] = &found[i]
+	}
+	return existing, nil
+}
+
+// UpsertBatch writes the products in a single transaction. A product with an
+// ID updates that row; otherwise it updates the product with the same name
+// (case-insensitive) in the same category, or is created.
+func (r *productRepository) UpsertBatch(ctx context.Context, products []*domain.Product) (int, int, error) {
+	created, updated := 0, 0
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		created, updated = 0, 0
+		for _, p := range products {
+			var existing domain.Product
+			var err error
+			if p.ID != 0 {
+				err = tx.First(&existing, p.ID).Error
+			} else {
+				err = tx.Where("category_id = ? AND LOWER(name) = LOWER(?)", p.CategoryID, p.Name).First(&existing).Error
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					if err := createProduct(tx, p); err != nil {
+						return err
+					}
+					created++
+					continue
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("product %d: %w", p.ID, err)
+			}
+
+			// Imports carry no attributes, tags or reorder threshold; an
+			// update keeps the product's. Tags are kept by leaving them nil.
+			p.ID = existing.ID
+			p.CreatedAt = existing.CreatedAt
+			p.Attributes = existing.Attributes
+			p.ReorderThreshold = existing.ReorderThreshold
+			if err := saveProduct(tx, p); err != nil {
+				return err
+			}
+			updated++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return created, updated, nil
 }
 
-func (r *productRepository) Delete(ctx context.Context, id int) error {
-	return r.db.WithContext(ctx).Delete(&domain.Product{}, id).Error
+// Transaction runs fn with a repository bound to a single database
+// transaction, committed when fn returns nil.
+func (r *productRepository) Transaction(ctx context.Context, fn func(repo domain.ProductRepository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&productRepository{db: tx})
+	})
 }
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/locale"
+)
+
+// productSearchQuery parses a web-search style query, such as
+// `laptop -gaming "16 gb"`, in both configurations of products.search_vector
+// and matches a product found by either. It takes the query text twice.
+const productSearchQuery = "(websearch_to_tsquery('indonesian', ?) || websearch_to_tsquery('english', ?))"
+
+// localizedProductColumn is the column of the product's text in lang: that of
+// its translation into lang when it has one, else its own. It takes no
+// arguments in the default locale and lang otherwise.
+func localizedProductColumn(column, lang string) (string, []interface{}) {
+	if lang == locale.Default {
+		return "products." + column, nil
+	}
+	return "COALESCE((SELECT pt." + column + " FROM product_translations pt WHERE pt.product_id = products.id AND pt.locale = ?), products." + column + ")", []interface{}{lang}
+}
+
+// productSearchCondition matches the products whose text in lang matches a
+// search for q. Products and translations are tested apart so both search
+// indexes can be used.
+func productSearchCondition(q, lang string) (string, []interface{}) {
+	if lang == locale.Default {
+		return "products.search_vector @@ " + productSearchQuery, []interface{}{q, q}
+	}
+	return "((products.search_vector @@ " + productSearchQuery + " AND NOT EXISTS (SELECT 1 FROM product_translations pt WHERE pt.product_id = products.id AND pt.locale = ?))" +
+			" OR EXISTS (SELECT 1 FROM product_translations pt WHERE pt.product_id = products.id AND pt.locale = ? AND pt.search_vector @@ " + productSearchQuery + "))",
+		[]interface{}{q, q, lang, lang, q, q}
+}
+
+// productSearchRank is the relevance of a product's text in lang to a search
+// for q. Name matches count more than description matches through the
+// weights of the search vectors.
+func productSearchRank(q, lang string) (string, []interface{}) {
+	vector, args := localizedProductColumn("search_vector", lang)
+	return "ts_rank(" + vector + ", " + productSearchQuery + ")", append(args, q, q)
+}
+
+// productSearchColumns selects the relevance of a search for q as
+// search_rank, and the name and best description fragments of the product's
+// text in lang with the matching words wrapped in <mark> as name_highlight
+// and description_highlight, or nothing when q is empty. ts_headline works
+// in a single configuration; English is used, whose stemmer leaves most
+// Indonesian words as they are. The text is HTML-escaped first, so the
+// <mark> tags are the only markup in the highlights; the parser reads the
+// entities as such, which keeps them whole and out of the matches.
+func productSearchColumns(q, lang string) (string, []interface{}) {
+	if q == "" {
+		return "", nil
+	}
+	rank, args := productSearchRank(q, lang)
+	name, nameArgs := localizedProductColumn("name", lang)
+	description, descriptionArgs := localizedProductColumn("description", lang)
+	args = append(append(args, nameArgs...), q, q)
+	args = append(append(args, descriptionArgs...), q, q)
+	return ", " + rank + " AS search_rank, " +
+		"ts_headline('english', " + htmlEscapeSQL(name) + ", " + productSearchQuery + ", 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') AS name_highlight, " +
+		"ts_headline('english', " + htmlEscapeSQL(description) + ", " + productSearchQuery + ", 'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5') AS description_highlight", args
+}
+
+// htmlEscapeSQL escapes the characters of the text expression expr that are
+// special in HTML; & goes first so the entities added are left alone.
+func htmlEscapeSQL(expr string) string {
+	return "replace(replace(replace(replace(replace(" + expr + `, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+}
+
+// Suggest returns the active products whose name, or whose category's name,
+// resembles q by trigram word similarity, best first. Names are those in the
+// locale of ctx. Candidates come from the <% operator, which uses the
+// trigram indexes on the names and their translations, so the query stays
+// fast as the catalog grows; a candidate found by a name that is not the
+// one in the locale is dropped again.
+func (r *productRepository) Suggest(ctx context.Context, q string, limit int) ([]dto.ProductSuggestion, error) {
+	var rows []struct {
+		ID           uint
+		Name         string
+		Slug         string
+		CategoryID   uint
+		CategoryName string
+		CategorySlug string
+		NameScore    float64
+		CatScore     float64
+	}
+	err := r.db.WithContext(ctx).Raw(`SELECT p.id, l.name, p.slug, c.id AS category_id, l.category_name, c.slug AS category_slug,
+			word_similarity(@q, l.name) AS name_score, word_similarity(@q, l.category_name) AS cat_score
+		FROM products p JOIN categories c ON c.id = p.category_id
+		LEFT JOIN product_translations pt ON pt.product_id = p.id AND pt.locale = @locale
+		LEFT JOIN category_translations ct ON ct.category_id = c.id AND ct.locale = @locale
+		CROSS JOIN LATERAL (SELECT COALESCE(pt.name, p.name) AS name, COALESCE(ct.name, c.name) AS category_name) l
+		WHERE p.is_active AND (@q <% l.name OR @q <% l.category_name) AND p.id IN (
+			SELECT id FROM products WHERE @q <% name
+			UNION
+			SELECT product_id FROM product_translations WHERE locale = @locale AND @q <% name
+			UNION
+			SELECT cp.id FROM products cp JOIN categories cc ON cc.id = cp.category_id WHERE @q <% cc.name
+			UNION
+			SELECT cp.id FROM products cp JOIN category_translations cct ON cct.category_id = cp.category_id WHERE cct.locale = @locale AND @q <% cct.name)
+		ORDER BY GREATEST(word_similarity(@q, l.name), word_similarity(@q, l.category_name)) DESC, l.name, p.id
+		LIMIT @limit`, sql.Named("q", q), sql.Named("locale", domain.LocaleFromContext(ctx)), sql.Named("limit", limit)).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]dto.ProductSuggestion, 0, len(rows))
+	for _, row := range rows {
+		suggestion := dto.ProductSuggestion{
+			ID:   row.ID,
+			Name: row.Name,
+			Slug: row.Slug,
+			Category: dto.SuggestionCategory{
+				ID:   row.CategoryID,
+				Name: row.CategoryName,
+				Slug: row.CategorySlug,
+			},
+			Matched: "product",
+			Score:   row.NameScore,
+		}
+		if row.CatScore > row.NameScore {
+			suggestion.Matched = "category"
+			suggestion.Score = row.CatScore
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+	return suggestions, nil
+}
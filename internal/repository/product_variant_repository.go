@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// variantAvailableQuantity is the variant's stock minus the quantity held by
+// active reservations.
+const variantAvailableQuantity = "GREATEST(product_variants.stock_quantity - COALESCE((SELECT SUM(ri.quantity) FROM reservation_items ri JOIN reservations rs ON rs.id = ri.reservation_id " +
+	"WHERE ri.variant_id = product_variants.id AND rs.status = 'active' AND rs.expires_at > now()), 0), 0)"
+
+type productVariantRepository struct {
+	db *gorm.DB
+}
+
+func NewProductVariantRepository(db *gorm.DB) domain.ProductVariantRepository {
+	return &productVariantRepository{
+		db: db,
+	}
+}
+
+// GetByProductID lists the variants of a product, or returns
+// domain.ErrProductNotFound when the product does not exist.
+func (r *productVariantRepository) GetByProductID(ctx context.Context, productID int) ([]domain.ProductVariant, error) {
+	db := r.db.WithContext(ctx)
+	var count int64
+	if err := db.Model(&domain.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, domain.ErrProductNotFound
+	}
+
+	variants := []domain.ProductVariant{}
+	err := db.Scopes(orderVariants).Where("product_id = ?", productID).Find(&variants).Error
+	return variants, err
+}
+
+func (r *productVariantRepository) GetByID(ctx context.Context, productID int, id int) (*domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	err := r.db.WithContext(ctx).Scopes(orderVariants).Where("product_id = ?", productID).First(&variant, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrVariantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// Create inserts the variant with no stock, then adds its opening stock
+// through the ledger.
+func (r *productVariantRepository) Create(ctx context.Context, variant *domain.ProductVariant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkProductCurrency(tx, variant.ProductID, variant.Price.Currency); err != nil {
+			return err
+		}
+		opening := variant.StockQuantity
+		variant.StockQuantity = 0
+		err := tx.Create(variant).Error
+		variant.StockQuantity = opening
+		if errors.Is(err, gorm.ErrForeignKeyViolated) {
+			return domain.ErrProductNotFound
+		}
+		if err != nil {
+			return translateError(err)
+		}
+		if err := recordOpeningStock(tx, variant.ProductID, &variant.ID, opening); err != nil {
+			return err
+		}
+		return scanVariantStock(tx, variant)
+	})
+}
+
+// Edit saves the variant but its stock quantity, which only changes through
+// stock movements and is read back.
+func (r *productVariantRepository) Edit(ctx context.Context, variant *domain.ProductVariant) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := checkProductCurrency(tx, variant.ProductID, variant.Price.Currency); err != nil {
+			return err
+		}
+		if err := tx.Omit("StockQuantity").Save(variant).Error; err != nil {
+			return translateError(err)
+		}
+		return scanVariantStock(tx, variant)
+	})
+}
+
+// Delete removes the variant together with its stock levels, which have no
+// foreign key on the variant.
+func (r *productVariantRepository) Delete(ctx context.Context, productID int, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("product_id = ?", productID).Delete(&domain.ProductVariant{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrVariantNotFound
+		}
+		return tx.Where("product_id = ? AND variant_id = ?", productID, id).Delete(&domain.StockLevel{}).Error
+	})
+}
+
+// scanVariantStock reads back the stock of the variant and the quantity of
+// it that is available.
+func scanVariantStock(tx *gorm.DB, variant *domain.ProductVariant) error {
+	return tx.Model(&domain.ProductVariant{}).Select("stock_quantity, "+variantAvailableQuantity).Where("id = ?", variant.ID).
+		Row().Scan(&variant.StockQuantity, &variant.AvailableQuantity)
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reservationRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationRepository(db *gorm.DB) domain.ReservationRepository {
+	return &reservationRepository{
+		db: db,
+	}
+}
+
+// stockRow is a locked product or variant row with the quantity held on it.
+type stockRow struct {
+	ID            uint
+	ProductID     uint
+	StockQuantity int
+	Held          int
+}
+
+func (r *reservationRepository) GetByID(ctx context.Context, id int) (*domain.Reservation, error) {
+	var reservation domain.Reservation
+	err := r.db.WithContext(ctx).Preload("Items", orderReservationItems).First(&reservation, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrReservationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// Create inserts the reservation when every item fits in the available
+// quantity. The stock rows of all items are locked first, products before
+// variants and each by ID, so concurrent reservations and stock movements
+// on the same stock wait for each other, and the consistent order keeps
+// them from deadlocking.
+func (r *reservationRepository) Create(ctx context.Context, reservation *domain.Reservation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productQty := map[uint]int{}
+		variantQty := map[uint]int{}
+		variantProduct := map[uint]uint{}
+		for _, item := range reservation.Items {
+			if item.VariantID == nil {
+				productQty[item.ProductID] += item.Quantity
+				continue
+			}
+			variantQty[*item.VariantID] += item.Quantity
+			variantProduct[*item.VariantID] = item.ProductID
+		}
+
+		now := time.Now()
+		products, err := lockStockRows(tx, "products", slices.Sorted(maps.Keys(productQty)), now)
+		if err != nil {
+			return err
+		}
+		if len(products) != len(productQty) {
+			return domain.ErrProductNotFound
+		}
+		for _, p := range products {
+			if p.StockQuantity-p.Held < productQty[p.ID] {
+				return fmt.Errorf("product %d: %w", p.ID, domain.ErrInsufficientStock)
+			}
+		}
+
+		variants, err := lockStockRows(tx, "product_variants", slices.Sorted(maps.Keys(variantQty)), now)
+		if err != nil {
+			return err
+		}
+		if len(variants) != len(variantQty) {
+			return domain.ErrVariantNotFound
+		}
+		for _, v := range variants {
+			if v.ProductID != variantProduct[v.ID] {
+				return domain.ErrVariantNotFound
+			}
+			if v.StockQuantity-v.Held < variantQty[v.ID] {
+				return fmt.Errorf("variant %d: %w", v.ID, domain.ErrInsufficientStock)
+			}
+		}
+
+		return tx.Create(reservation).Error
+	})
+}
+
+// lockStockRows locks the rows of table with the given IDs, in ID order, and
+// returns their stock with the quantity held by active reservations.
+func lockStockRows(tx *gorm.DB, table string, ids []uint, now time.Time) ([]stockRow, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	productID, heldOn := "id", "ri.product_id = s.id AND ri.variant_id IS NULL"
+	if table == "product_variants" {
+		productID, heldOn = "product_id", "ri.variant_id = s.id"
+	}
+
+	var rows []stockRow
+	err := tx.Raw("SELECT id, "+productID+" AS product_id, stock_quantity FROM "+table+" WHERE id IN ? ORDER BY id FOR UPDATE", ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var held []struct {
+		ID   uint
+		Held int
+	}
+	err = tx.Raw("SELECT s.id, SUM(ri.quantity) AS held FROM "+table+" s "+
+		"JOIN reservation_items ri ON "+heldOn+" "+
+		"JOIN reservations rs ON rs.id = ri.reservation_id "+
+		"WHERE s.id IN ? AND rs.status = ? AND rs.expires_at > ? GROUP BY s.id",
+		ids, dto.ReservationActive, now).Scan(&held).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range held {
+		for i := range rows {
+			if rows[i].ID == h.ID {
+				rows[i].Held = h.Held
+			}
+		}
+	}
+	return rows, nil
+}
+
+// Confirm turns an active reservation into sale movements taken from the
+// warehouse, the default one when warehouseID is nil, and closes it.
+func (r *reservationRepository) Confirm(ctx context.Context, id int, warehouseID *uint) (*domain.Reservation, error) {
+	var reservation domain.Reservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockActiveReservation(tx, id, &reservation); err != nil {
+			return err
+		}
+		if !reservation.ExpiresAt.After(time.Now()) {
+			return domain.ErrReservationExpired
+		}
+		// Closed first, so the sales may take the stock it held.
+		reservation.Status = dto.ReservationConfirmed
+		if err := tx.Model(&reservation).Update("status", reservation.Status).Error; err != nil {
+			return err
+		}
+
+		// Apply the items in the order Create locks their stock rows in.
+		items := slices.Clone(reservation.Items)
+		sort.SliceStable(items, func(i, j int) bool {
+			if (items[i].VariantID == nil) != (items[j].VariantID == nil) {
+				return items[i].VariantID == nil
+			}
+			if items[i].VariantID == nil {
+				return items[i].ProductID < items[j].ProductID
+			}
+			return *items[i].VariantID < *items[j].VariantID
+		})
+		for _, item := range items {
+			err := applyStockMovement(tx, &domain.StockMovement{
+				ProductID:   item.ProductID,
+				VariantID:   item.VariantID,
+				WarehouseID: warehouseID,
+				Type:        dto.MovementSale,
+				Quantity:    -item.Quantity,
+				Reason:      fmt.Sprintf("reservation #%d confirmed", reservation.ID),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// Release closes an active reservation, so its hold stops counting. An
+// expired hold that has not been swept yet can still be released.
+func (r *reservationRepository) Release(ctx context.Context, id int) (*domain.Reservation, error) {
+	var reservation domain.Reservation
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockActiveReservation(tx, id, &reservation); err != nil {
+			return err
+		}
+		reservation.Status = dto.ReservationReleased
+		return tx.Model(&reservation).Update("status", reservation.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ExpireHolds marks the active reservations that expired by now as expired
+// and returns how many there were.
+func (r *reservationRepository) ExpireHolds(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&domain.Reservation{}).
+		Where("status = ? AND expires_at <= ?", dto.ReservationActive, now).
+		Update("status", dto.ReservationExpired)
+	return result.RowsAffected, result.Error
+}
+
+// lockActiveReservation loads the reservation with its items and locks it
+// against concurrent confirmation, release and sweeping.
+func lockActiveReservation(tx *gorm.DB, id int, reservation *domain.Reservation) error {
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(reservation, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrReservationNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if reservation.Status == dto.ReservationExpired {
+		return domain.ErrReservationExpired
+	}
+	if reservation.Status != dto.ReservationActive {
+		return domain.ErrReservationClosed
+	}
+	return tx.Scopes(orderReservationItems).Where("reservation_id = ?", reservation.ID).Find(&reservation.Items).Error
+}
+
+func orderReservationItems(db *gorm.DB) *gorm.DB {
+	return db.Order("reservation_items.id")
+}
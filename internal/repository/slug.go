@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/slug"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// slugBase is the slug for name before any numeric suffix. Names without any
+// ASCII-compatible characters fall back to the entity type.
+func slugBase(entityType, name string) string {
+	if base := slug.Make(name); base != "" {
+		return base
+	}
+	return entityType
+}
+
+// slugFits reports whether s is base or base with a numeric suffix, i.e. a
+// slug that could have been generated for the same name.
+func slugFits(s, base string) bool {
+	if s == base {
+		return true
+	}
+	suffix, ok := strings.CutPrefix(s, base+"-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// uniqueSlug returns the slug for name, suffixed with -2, -3, ... when it is
+// used by another row of table or held in the slug history of another
+// entity. selfID is the row being renamed, or 0 for a new row.
+func uniqueSlug(tx *gorm.DB, table, entityType, name string, selfID uint) (string, error) {
+	base := slugBase(entityType, name)
+	pattern := base + "-%"
+
+	var taken []string
+	err := tx.Table(table).
+		Where("(slug = ? OR slug LIKE ?) AND id <> ?", base, pattern, selfID).
+		Pluck("slug", &taken).Error
+	if err != nil {
+		return "", err
+	}
+	var reserved []string
+	err = tx.Model(&domain.SlugRedirect{}).
+		Where("entity_type = ? AND (slug = ? OR slug LIKE ?) AND entity_id <> ?", entityType, base, pattern, selfID).
+		Pluck("slug", &reserved).Error
+	if err != nil {
+		return "", err
+	}
+
+	used := make(map[string]bool, len(taken)+len(reserved))
+	for _, s := range append(taken, reserved...) {
+		used[s] = true
+	}
+	candidate := base
+	for n := 2; used[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+	return candidate, nil
+}
+
+// renameSlug returns the slug a row should have after being given name. The
+// current slug is kept while it still fits the name; otherwise a new one is
+// generated and the old one moves to the slug history. Returning to a
+// previously used slug takes it back out of the history.
+func renameSlug(tx *gorm.DB, table, entityType string, id uint, name string) (string, error) {
+	var current string
+	err := tx.Table(table).Select("slug").Where("id = ?", id).Scan(&current).Error
+	if err != nil {
+		return "", err
+	}
+	if current != "" && slugFits(current, slugBase(entityType, name)) {
+		return current, nil
+	}
+
+	next, err := uniqueSlug(tx, table, entityType, name, id)
+	if err != nil {
+		return "", err
+	}
+	if current != "" {
+		redirect := domain.SlugRedirect{EntityType: entityType, Slug: current, EntityID: id}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&redirect).Error; err != nil {
+			return "", err
+		}
+	}
+	err = tx.Where("entity_type = ? AND slug = ?", entityType, next).Delete(&domain.SlugRedirect{}).Error
+	return next, err
+}
+
+// BackfillSlugs gives every category and product the slug the application
+// would have given it, as if they had been created in ID order: a name's
+// first row gets its base slug and later ones the suffixes uniqueSlug adds.
+func BackfillSlugs(tx *gorm.DB) error {
+	for _, entity := range []struct{ table, entityType string }{
+		{"categories", domain.SlugEntityCategory},
+		{"products", domain.SlugEntityProduct},
+	} {
+		// A placeholder no slug can equal frees every slug of the table.
+		if err := tx.Exec("UPDATE " + entity.table + " SET slug = '_' || id").Error; err != nil {
+			return err
+		}
+		var rows []struct {
+			ID   uint
+			Name string
+		}
+		if err := tx.Table(entity.table).Select("id", "name").Order("id").Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			s, err := uniqueSlug(tx, entity.table, entity.entityType, row.Name, row.ID)
+			if err != nil {
+				return err
+			}
+			if err := tx.Table(entity.table).Where("id = ?", row.ID).Update("slug", s).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// slugRedirectTarget is a subquery selecting the entity that used slug s
+// before a rename.
+func slugRedirectTarget(db *gorm.DB, entityType, s string) *gorm.DB {
+	return db.Model(&domain.SlugRedirect{}).Select("entity_id").Where("entity_type = ? AND slug = ?", entityType, s)
+}
+
+// deleteSlugHistory frees the retired slugs of a deleted entity.
+func deleteSlugHistory(tx *gorm.DB, entityType string, id int) error {
+	return tx.Where("entity_type = ? AND entity_id = ?", entityType, id).Delete(&domain.SlugRedirect{}).Error
+}
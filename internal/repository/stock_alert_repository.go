@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// productReorderThreshold is the threshold of a product, or else that of the
+// nearest category on the path from its category up to the root that sets
+// one.
+const productReorderThreshold = "COALESCE(products.reorder_threshold, (SELECT c.reorder_threshold FROM categories pc JOIN categories c ON pc.path LIKE c.path || '%' " +
+	"WHERE pc.id = products.category_id AND c.reorder_threshold IS NOT NULL ORDER BY length(c.path) DESC LIMIT 1))"
+
+const stockStatusColumns = "products.id AS product_id, products.name, products.slug, products.category_id, " +
+	productTotalStock + " AS stock_quantity, " + productReorderThreshold + " AS reorder_threshold"
+
+type stockAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewStockAlertRepository(db *gorm.DB) domain.StockAlertRepository {
+	return &stockAlertRepository{
+		db: db,
+	}
+}
+
+// GetStockStatus returns the stock status of the given products, or with no
+// IDs of every product that has a threshold or an open alert.
+func (r *stockAlertRepository) GetStockStatus(ctx context.Context, productIDs []uint) ([]dto.StockStatus, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Product{}).Select(stockStatusColumns)
+	if len(productIDs) > 0 {
+		query = query.Where("products.id IN ?", productIDs)
+	} else {
+		query = query.Where(productReorderThreshold + " IS NOT NULL OR EXISTS (SELECT 1 FROM stock_alerts a WHERE a.product_id = products.id AND a.resolved_at IS NULL)")
+	}
+
+	statuses := []dto.StockStatus{}
+	err := query.Order("products.id").Scan(&statuses).Error
+	return statuses, err
+}
+
+// GetLowStock lists the products at or below their reorder threshold, those
+// furthest below it first.
+func (r *stockAlertRepository) GetLowStock(ctx context.Context, pq dto.PaginationQuery) ([]dto.StockStatus, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.Product{}).
+		Where(productTotalStock + " <= " + productReorderThreshold)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	statuses := []dto.StockStatus{}
+	err := query.Select(stockStatusColumns).
+		Order(productTotalStock + " - " + productReorderThreshold + ", products.id").
+		Offset((pq.Page - 1) * pq.Limit).Limit(pq.Limit).
+		Scan(&statuses).Error
+	return statuses, total, err
+}
+
+// OpenAlert records an alert for the product unless one is already open. It
+// reports whether a new alert was opened, so concurrent evaluations notify
+// at most once.
+func (r *stockAlertRepository) OpenAlert(ctx context.Context, status dto.StockStatus) (bool, error) {
+	alert := domain.StockAlert{
+		ProductID:        status.ProductID,
+		StockQuantity:    status.StockQuantity,
+		ReorderThreshold: *status.ReorderThreshold,
+	}
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Omit("Product").Create(&alert)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ResolveAlert closes the open alert of the product, if any, and reports
+// whether there was one.
+func (r *stockAlertRepository) ResolveAlert(ctx context.Context, productID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.StockAlert{}).
+		Where("product_id = ? AND resolved_at IS NULL", productID).
+		Update("resolved_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+// DiscardAlert deletes the open alert of the product, if any, so the next
+// evaluation opens it again.
+func (r *stockAlertRepository) DiscardAlert(ctx context.Context, productID uint) error {
+	return r.db.WithContext(ctx).
+		Where("product_id = ? AND resolved_at IS NULL", productID).
+		Delete(&domain.StockAlert{}).Error
+}
@@ -0,0 +1,215 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type stockMovementRepository struct {
+	db *gorm.DB
+}
+
+func NewStockMovementRepository(db *gorm.DB) domain.StockMovementRepository {
+	return &stockMovementRepository{
+		db: db,
+	}
+}
+
+// Record applies the movement to the stock and appends it to the ledger in
+// one transaction.
+func (r *stockMovementRepository) Record(ctx context.Context, movement *domain.StockMovement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return applyStockMovement(tx, movement)
+	})
+}
+
+// Transfer applies a pair of transfer movements, one taking stock out of a
+// warehouse and one putting it into another, in one transaction. The stock
+// total of the product or variant is left unchanged.
+func (r *stockMovementRepository) Transfer(ctx context.Context, out *domain.StockMovement, in *domain.StockMovement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := applyStockMovement(tx, out); err != nil {
+			return err
+		}
+		return applyStockMovement(tx, in)
+	})
+}
+
+// GetByProductID returns the movements of a product, newest first.
+func (r *stockMovementRepository) GetByProductID(ctx context.Context, productID int, query dto.StockMovementQuery, pq dto.PaginationQuery) ([]domain.StockMovement, int64, error) {
+	db := r.db.WithContext(ctx)
+	var count int64
+	if err := db.Model(&domain.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+	if count == 0 {
+		return nil, 0, domain.ErrProductNotFound
+	}
+
+	q := db.Model(&domain.StockMovement{}).Where("product_id = ?", productID)
+	if query.Type != "" {
+		q = q.Where("type = ?", query.Type)
+	}
+	if query.VariantID != nil {
+		q = q.Where("variant_id = ?", *query.VariantID)
+	}
+	if query.WarehouseID != nil {
+		q = q.Where("warehouse_id = ?", *query.WarehouseID)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	movements := []domain.StockMovement{}
+	err := q.Order("created_at desc, id desc").
+		Offset((pq.Page - 1) * pq.Limit).Limit(pq.Limit).
+		Find(&movements).Error
+	return movements, total, err
+}
+
+// applyStockMovement adds movement.Quantity to the stock of the product, or
+// of its variant when VariantID is set, and to its level in the movement's
+// warehouse, the default one when WarehouseID is not set. It then inserts
+// the movement with the resulting total balance. Each change is a single
+// statement, so concurrent movements cannot lose each other, and the stock
+// check constraints turn an overdraw into domain.ErrInsufficientStock. A
+// movement taking stock out, other than a transfer, must also leave the
+// quantity held by active reservations. The product or variant row is
+// locked or updated first, which serializes movements and reservations of
+// the same stock. tx must be a transaction.
+func applyStockMovement(tx *gorm.DB, movement *domain.StockMovement) error {
+	if movement.WarehouseID == nil {
+		id, err := defaultWarehouseID(tx)
+		if err != nil {
+			return err
+		}
+		movement.WarehouseID = &id
+	}
+
+	now := time.Now()
+	if movement.Quantity < 0 && movement.Type != dto.MovementTransfer {
+		if err := checkUnheldStock(tx, movement, now); err != nil {
+			return err
+		}
+	}
+
+	var balance []int
+	var result *gorm.DB
+	if movement.VariantID != nil {
+		result = tx.Raw("UPDATE product_variants SET stock_quantity = stock_quantity + ?, updated_at = ? WHERE id = ? AND product_id = ? RETURNING stock_quantity",
+			movement.Quantity, now, *movement.VariantID, movement.ProductID).Scan(&balance)
+	} else {
+		result = tx.Raw("UPDATE products SET stock_quantity = stock_quantity + ?, updated_at = ? WHERE id = ? RETURNING stock_quantity",
+			movement.Quantity, now, movement.ProductID).Scan(&balance)
+	}
+	if errors.Is(result.Error, gorm.ErrCheckConstraintViolated) {
+		return domain.ErrInsufficientStock
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if len(balance) == 0 {
+		if movement.VariantID != nil {
+			return domain.ErrVariantNotFound
+		}
+		return domain.ErrProductNotFound
+	}
+	if err := applyStockLevel(tx, movement, now); err != nil {
+		return err
+	}
+
+	movement.BalanceAfter = balance[0]
+	if movement.Actor == "" {
+		movement.Actor = domain.ActorFromContext(tx.Statement.Context)
+	}
+	return tx.Omit("Product", "Variant", "Warehouse").Create(movement).Error
+}
+
+// checkUnheldStock locks the product or variant row of a movement taking
+// stock out and checks that the stock not held by active reservations
+// covers it. Transfers are not checked: they leave the total unchanged.
+func checkUnheldStock(tx *gorm.DB, movement *domain.StockMovement, now time.Time) error {
+	table, id := "products", movement.ProductID
+	if movement.VariantID != nil {
+		table, id = "product_variants", *movement.VariantID
+	}
+	rows, err := lockStockRows(tx, table, []uint{id}, now)
+	if err != nil {
+		return err
+	}
+	// A missing row is reported by the update.
+	if len(rows) > 0 && rows[0].StockQuantity-rows[0].Held < -movement.Quantity {
+		return domain.ErrInsufficientStock
+	}
+	return nil
+}
+
+// applyStockLevel adds movement.Quantity to the stock level of its location.
+// Stock is added with an upsert, as the level may not exist yet. Removing
+// stock only updates an existing level: Postgres checks constraints on the
+// row proposed by an upsert, so a negative one would fail even when the
+// level could cover it.
+func applyStockLevel(tx *gorm.DB, movement *domain.StockMovement, now time.Time) error {
+	var variantID uint
+	if movement.VariantID != nil {
+		variantID = *movement.VariantID
+	}
+
+	var result *gorm.DB
+	var level []int
+	if movement.Quantity >= 0 {
+		result = tx.Raw(`INSERT INTO stock_levels (warehouse_id, product_id, variant_id, quantity, updated_at) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (warehouse_id, product_id, variant_id) DO UPDATE SET quantity = stock_levels.quantity + EXCLUDED.quantity, updated_at = EXCLUDED.updated_at
+			RETURNING quantity`,
+			*movement.WarehouseID, movement.ProductID, variantID, movement.Quantity, now).Scan(&level)
+	} else {
+		result = tx.Raw("UPDATE stock_levels SET quantity = quantity + ?, updated_at = ? WHERE warehouse_id = ? AND product_id = ? AND variant_id = ? RETURNING quantity",
+			movement.Quantity, now, *movement.WarehouseID, movement.ProductID, variantID).Scan(&level)
+	}
+	if errors.Is(result.Error, gorm.ErrCheckConstraintViolated) {
+		return domain.ErrInsufficientStock
+	}
+	if errors.Is(result.Error, gorm.ErrForeignKeyViolated) {
+		return domain.ErrWarehouseNotFound
+	}
+	if result.Error != nil {
+		return result.Error
+	}
+	if len(level) == 0 {
+		return domain.ErrInsufficientStock
+	}
+	return nil
+}
+
+// defaultWarehouseID returns the ID of the default warehouse.
+func defaultWarehouseID(tx *gorm.DB) (uint, error) {
+	var ids []uint
+	if err := tx.Model(&domain.Warehouse{}).Where("is_default").Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, domain.ErrNoDefaultWarehouse
+	}
+	return ids[0], nil
+}
+
+// recordOpeningStock adds the stock a product or variant was created with,
+// which must have been inserted with none, to the default warehouse.
+func recordOpeningStock(tx *gorm.DB, productID uint, variantID *uint, quantity int) error {
+	if quantity == 0 {
+		return nil
+	}
+	return applyStockMovement(tx, &domain.StockMovement{
+		ProductID: productID,
+		VariantID: variantID,
+		Type:      dto.MovementReceipt,
+		Quantity:  quantity,
+		Reason:    "opening stock",
+	})
+}
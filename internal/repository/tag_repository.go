@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) domain.TagRepository {
+	return &tagRepository{
+		db: db,
+	}
+}
+
+func (r *tagRepository) GetAll(ctx context.Context) ([]domain.Tag, error) {
+	tags := []domain.Tag{}
+	err := r.db.WithContext(ctx).Order("name, id").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) GetByID(ctx context.Context, id int) (*domain.Tag, error) {
+	var tag domain.Tag
+	err := r.db.WithContext(ctx).First(&tag, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetBySlugs returns the tags with the given slugs, leaving out unknown ones.
+func (r *tagRepository) GetBySlugs(ctx context.Context, slugs []string) ([]domain.Tag, error) {
+	tags := []domain.Tag{}
+	if len(slugs) == 0 {
+		return tags, nil
+	}
+	err := r.db.WithContext(ctx).Where("slug IN ?", slugs).Order("name, id").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) Create(ctx context.Context, tag *domain.Tag) error {
+	return translateError(r.db.WithContext(ctx).Create(tag).Error)
+}
+
+func (r *tagRepository) Edit(ctx context.Context, tag *domain.Tag) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current domain.Tag
+		if err := tx.First(&current, tag.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrTagNotFound
+			}
+			return err
+		}
+		tag.CreatedAt = current.CreatedAt
+		return translateError(tx.Save(tag).Error)
+	})
+}
+
+// Delete removes the tag; the database removes it from its products.
+func (r *tagRepository) Delete(ctx context.Context, id int) error {
+	result := r.db.WithContext(ctx).Delete(&domain.Tag{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTagNotFound
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type translationRepository struct {
+	db *gorm.DB
+}
+
+func NewTranslationRepository(db *gorm.DB) domain.TranslationRepository {
+	return &translationRepository{
+		db: db,
+	}
+}
+
+// GetProductTranslations lists the translations of a product by locale, or
+// returns domain.ErrProductNotFound when the product does not exist.
+func (r *translationRepository) GetProductTranslations(ctx context.Context, productID int) ([]domain.ProductTranslation, error) {
+	db := r.db.WithContext(ctx)
+	if err := checkProduct(db, uint(productID)); err != nil {
+		return nil, err
+	}
+	translations := []domain.ProductTranslation{}
+	err := db.Where("product_id = ?", productID).Order("locale").Find(&translations).Error
+	return translations, err
+}
+
+func (r *translationRepository) ProductTranslations(ctx context.Context, locale string, productIDs []uint) (map[uint]domain.ProductTranslation, error) {
+	var translations []domain.ProductTranslation
+	if len(productIDs) > 0 {
+		err := r.db.WithContext(ctx).Where("locale = ? AND product_id IN ?", locale, productIDs).Find(&translations).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uint]domain.ProductTranslation, len(translations))
+	for _, t := range translations {
+		byID[t.ProductID] = t
+	}
+	return byID, nil
+}
+
+// SaveProductTranslation creates or replaces the product's translation into
+// its locale.
+func (r *translationRepository) SaveProductTranslation(ctx context.Context, translation *domain.ProductTranslation) error {
+	db := r.db.WithContext(ctx)
+	if err := checkProduct(db, translation.ProductID); err != nil {
+		return err
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+	}).Create(translation).Error
+}
+
+func (r *translationRepository) DeleteProductTranslation(ctx context.Context, productID int, locale string) error {
+	result := r.db.WithContext(ctx).Where("product_id = ? AND locale = ?", productID, locale).Delete(&domain.ProductTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTranslationNotFound
+	}
+	return nil
+}
+
+// GetCategoryTranslations lists the translations of a category by locale,
+// or returns domain.ErrCategoryNotFound when the category does not exist.
+func (r *translationRepository) GetCategoryTranslations(ctx context.Context, categoryID int) ([]domain.CategoryTranslation, error) {
+	db := r.db.WithContext(ctx)
+	if err := checkCategory(db, uint(categoryID)); err != nil {
+		return nil, err
+	}
+	translations := []domain.CategoryTranslation{}
+	err := db.Where("category_id = ?", categoryID).Order("locale").Find(&translations).Error
+	return translations, err
+}
+
+func (r *translationRepository) CategoryTranslations(ctx context.Context, locale string, categoryIDs []uint) (map[uint]domain.CategoryTranslation, error) {
+	var translations []domain.CategoryTranslation
+	if len(categoryIDs) > 0 {
+		err := r.db.WithContext(ctx).Where("locale = ? AND category_id IN ?", locale, categoryIDs).Find(&translations).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uint]domain.CategoryTranslation, len(translations))
+	for _, t := range translations {
+		byID[t.CategoryID] = t
+	}
+	return byID, nil
+}
+
+// SaveCategoryTranslation creates or replaces the category's translation
+// into its locale.
+func (r *translationRepository) SaveCategoryTranslation(ctx context.Context, translation *domain.CategoryTranslation) error {
+	db := r.db.WithContext(ctx)
+	if err := checkCategory(db, translation.CategoryID); err != nil {
+		return err
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category_id"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+	}).Create(translation).Error
+}
+
+func (r *translationRepository) DeleteCategoryTranslation(ctx context.Context, categoryID int, locale string) error {
+	result := r.db.WithContext(ctx).Where("category_id = ? AND locale = ?", categoryID, locale).Delete(&domain.CategoryTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrTranslationNotFound
+	}
+	return nil
+}
+
+func (r *translationRepository) GetMissingProducts(ctx context.Context, locales []string, pq dto.PaginationQuery) ([]dto.MissingTranslation, int64, error) {
+	return missingTranslations(r.db.WithContext(ctx), "products", "product_translations", "product_id", locales, pq)
+}
+
+func (r *translationRepository) GetMissingCategories(ctx context.Context, locales []string, pq dto.PaginationQuery) ([]dto.MissingTranslation, int64, error) {
+	return missingTranslations(r.db.WithContext(ctx), "categories", "category_translations", "category_id", locales, pq)
+}
+
+// missingTranslations pairs every row of table with every locale and keeps
+// the pairs without a row in translations, ordered by ID then locale.
+func missingTranslations(db *gorm.DB, table, translations, foreignKey string, locales []string, pq dto.PaginationQuery) ([]dto.MissingTranslation, int64, error) {
+	query := db.Table(table+" AS e").
+		Joins("CROSS JOIN unnest(string_to_array(?, ',')) AS l(locale)", strings.Join(locales, ",")).
+		Where("NOT EXISTS (SELECT 1 FROM " + translations + " t WHERE t." + foreignKey + " = e.id AND t.locale = l.locale)")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	missing := []dto.MissingTranslation{}
+	err := query.Select("e.id, e.name, e.slug, l.locale").
+		Order("e.id, l.locale").
+		Offset((pq.Page - 1) * pq.Limit).Limit(pq.Limit).
+		Scan(&missing).Error
+	return missing, total, err
+}
+
+// checkProduct returns domain.ErrProductNotFound when the product does not
+// exist.
+func checkProduct(db *gorm.DB, productID uint) error {
+	var count int64
+	if err := db.Model(&domain.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return domain.ErrProductNotFound
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"test-elabram/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type validationRepository struct {
+	db *gorm.DB
+}
+
+func NewValidationRepository(db *gorm.DB) domain.ValidationRepository {
+	return &validationRepository{
+		db: db,
+	}
+}
+
+func (r *validationRepository) CategoryExists(ctx context.Context, id uint) (bool, error) {
+	var exists bool
+	err := r.db.WithContext(ctx).Raw("SELECT EXISTS (SELECT 1 FROM categories WHERE id = ?)", id).Scan(&exists).Error
+	return exists, err
+}
+
+func (r *validationRepository) ProductNameTaken(ctx context.Context, categoryID uint, name string, excludeID uint) (bool, error) {
+	var taken bool
+	err := r.db.WithContext(ctx).
+		Raw("SELECT EXISTS (SELECT 1 FROM products WHERE category_id = ? AND lower(name) = lower(?) AND id <> ?)", categoryID, name, excludeID).
+		Scan(&taken).Error
+	return taken, err
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type warehouseRepository struct {
+	db *gorm.DB
+}
+
+func NewWarehouseRepository(db *gorm.DB) domain.WarehouseRepository {
+	return &warehouseRepository{
+		db: db,
+	}
+}
+
+func (r *warehouseRepository) GetAll(ctx context.Context) ([]domain.Warehouse, error) {
+	warehouses := []domain.Warehouse{}
+	err := r.db.WithContext(ctx).Order("id").Find(&warehouses).Error
+	return warehouses, err
+}
+
+func (r *warehouseRepository) GetByID(ctx context.Context, id int) (*domain.Warehouse, error) {
+	var warehouse domain.Warehouse
+	err := r.db.WithContext(ctx).First(&warehouse, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrWarehouseNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// Create inserts the warehouse. When it is the default one, the previous
+// default is unset in the same transaction.
+func (r *warehouseRepository) Create(ctx context.Context, warehouse *domain.Warehouse) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := unsetDefaultWarehouse(tx, warehouse); err != nil {
+			return err
+		}
+		return translateError(tx.Create(warehouse).Error)
+	})
+	return r.writeError(ctx, warehouse, err)
+}
+
+// Edit saves the warehouse. The default flag can only be moved to another
+// warehouse, not removed, so there always is a default to put stock in.
+func (r *warehouseRepository) Edit(ctx context.Context, warehouse *domain.Warehouse) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current domain.Warehouse
+		if err := tx.First(&current, warehouse.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrWarehouseNotFound
+			}
+			return err
+		}
+		if current.IsDefault {
+			warehouse.IsDefault = true
+		}
+		if err := unsetDefaultWarehouse(tx, warehouse); err != nil {
+			return err
+		}
+		warehouse.CreatedAt = current.CreatedAt
+		return translateError(tx.Save(warehouse).Error)
+	})
+	return r.writeError(ctx, warehouse, err)
+}
+
+// writeError tells apart the unique indexes a write of the warehouse broke,
+// once its transaction is rolled back: its code is another warehouse's, or
+// else another warehouse was made the default at the same time.
+func (r *warehouseRepository) writeError(ctx context.Context, warehouse *domain.Warehouse, err error) error {
+	if !errors.Is(err, domain.ErrConflict) {
+		return err
+	}
+	var taken bool
+	if qErr := r.db.WithContext(ctx).Raw("SELECT EXISTS (SELECT 1 FROM warehouses WHERE code = ? AND id <> ?)", warehouse.Code, warehouse.ID).Scan(&taken).Error; qErr != nil {
+		return qErr
+	}
+	if taken {
+		return domain.ErrWarehouseCodeTaken
+	}
+	return domain.ErrDefaultChanged
+}
+
+// Delete removes an empty warehouse other than the default one. Its stock
+// levels go with it and its movements keep no warehouse. The row lock makes
+// a concurrent movement into the warehouse either finish first or fail.
+func (r *warehouseRepository) Delete(ctx context.Context, id int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var warehouse domain.Warehouse
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&warehouse, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrWarehouseNotFound
+			}
+			return err
+		}
+		if warehouse.IsDefault {
+			return domain.ErrDefaultWarehouse
+		}
+
+		var stocked int64
+		err := tx.Model(&domain.StockLevel{}).Where("warehouse_id = ? AND quantity > 0", id).Limit(1).Count(&stocked).Error
+		if err != nil {
+			return err
+		}
+		if stocked > 0 {
+			return domain.ErrWarehouseNotEmpty
+		}
+		return tx.Delete(&warehouse).Error
+	})
+}
+
+// GetStockLevels lists the non-empty stock levels of a warehouse by product.
+func (r *warehouseRepository) GetStockLevels(ctx context.Context, warehouseID int) ([]domain.StockLevel, error) {
+	if _, err := r.GetByID(ctx, warehouseID); err != nil {
+		return nil, err
+	}
+	levels := []domain.StockLevel{}
+	err := r.db.WithContext(ctx).
+		Where("warehouse_id = ? AND quantity > 0", warehouseID).
+		Order("product_id, variant_id").
+		Find(&levels).Error
+	return levels, err
+}
+
+// GetProductStockLevels lists where the stock of a product and its variants
+// is held, or returns domain.ErrProductNotFound when the product does not
+// exist.
+func (r *warehouseRepository) GetProductStockLevels(ctx context.Context, productID int) ([]domain.StockLevel, error) {
+	db := r.db.WithContext(ctx)
+	var count int64
+	if err := db.Model(&domain.Product{}).Where("id = ?", productID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, domain.ErrProductNotFound
+	}
+
+	levels := []domain.StockLevel{}
+	err := db.Preload("Warehouse").
+		Where("product_id = ? AND quantity > 0", productID).
+		Order("warehouse_id, variant_id").
+		Find(&levels).Error
+	return levels, err
+}
+
+// unsetDefaultWarehouse clears the default flag of every other warehouse
+// when warehouse is to become the default.
+func unsetDefaultWarehouse(tx *gorm.DB, warehouse *domain.Warehouse) error {
+	if !warehouse.IsDefault {
+		return nil
+	}
+	return tx.Model(&domain.Warehouse{}).
+		Where("is_default AND id <> ?", warehouse.ID).
+		Update("is_default", false).Error
+}
@@ -0,0 +1,79 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the longest base slug Make returns, before any numeric suffix
+// added to make it unique.
+const MaxLength = 80
+
+// Letters that do not decompose into an ASCII base letter plus accents.
+var transliterations = map[rune]string{
+	'ß': "ss",
+	'æ': "ae",
+	'Æ': "ae",
+	'œ': "oe",
+	'Œ': "oe",
+	'ø': "o",
+	'Ø': "o",
+	'đ': "d",
+	'Đ': "d",
+	'ð': "d",
+	'Ð': "d",
+	'ł': "l",
+	'Ł': "l",
+	'þ': "th",
+	'Þ': "th",
+	'ı': "i",
+	'&': "and",
+}
+
+// Make turns s into a lowercase URL slug made of ASCII letters, digits and
+// single hyphens, e.g. "Café Crème 250g" becomes "cafe-creme-250g". Accents
+// are stripped and characters without an ASCII equivalent are dropped, so the
+// result may be empty.
+func Make(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range norm.NFKD.String(s) {
+		if unicode.Is(unicode.Mn, r) || r == '\'' || r == '’' {
+			continue
+		}
+		t, ok := transliterations[r]
+		if !ok {
+			r = unicode.ToLower(r)
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+				hyphen = true
+				continue
+			}
+			t = string(r)
+		}
+		// "&" is spelled out as a word of its own.
+		if r == '&' {
+			hyphen = true
+		}
+		if hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		hyphen = r == '&'
+		b.WriteString(t)
+	}
+	return truncate(b.String())
+}
+
+// truncate shortens a slug to MaxLength, cutting at a hyphen when possible
+// so words are not split.
+func truncate(s string) string {
+	if len(s) <= MaxLength {
+		return s
+	}
+	s = s[:MaxLength]
+	if i := strings.LastIndexByte(s, '-'); i > 0 {
+		s = s[:i]
+	}
+	return strings.TrimRight(s, "-")
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+)
+
+type categoryAttributeUsecase struct {
+	attributeRepository domain.CategoryAttributeRepository
+	validator           domain.RequestValidator
+}
+
+func NewCategoryAttributeUsecase(attributeRepository domain.CategoryAttributeRepository, validator domain.RequestValidator) domain.CategoryAttributeUsecase {
+	return &categoryAttributeUsecase{
+		attributeRepository: attributeRepository,
+		validator:           validator,
+	}
+}
+
+// GetAttributes returns the attributes that apply to the products of the
+// category, including those defined on its ancestors.
+func (u *categoryAttributeUsecase) GetAttributes(ctx context.Context, categoryID int) ([]domain.CategoryAttribute, error) {
+	if categoryID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.attributeRepository.GetEffective(ctx, uint(categoryID))
+}
+
+func (u *categoryAttributeUsecase) CreateAttribute(ctx context.Context, categoryID int, req *dto.CategoryAttributeRequest) (*domain.CategoryAttribute, error) {
+	if categoryID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	if err := u.checkAttributeOptions(ctx, req.Type, req.Options); err != nil {
+		return nil, err
+	}
+	attribute := domain.CategoryAttribute{
+		CategoryID: uint(categoryID),
+		Name:       req.Name,
+		Type:       req.Type,
+		Options:    req.Options,
+		Required:   req.Required,
+	}
+	if err := u.attributeRepository.Create(ctx, &attribute); err != nil {
+		return nil, err
+	}
+	return &attribute, nil
+}
+
+// EditAttribute replaces the options and required flag of the attribute.
+// Products are not checked against the change; they are on their next save.
+func (u *categoryAttributeUsecase) EditAttribute(ctx context.Context, categoryID int, id int, req *dto.UpdateCategoryAttributeRequest) (*domain.CategoryAttribute, error) {
+	if categoryID <= 0 || id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	attribute, err := u.attributeRepository.GetByID(ctx, categoryID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkAttributeOptions(ctx, attribute.Type, req.Options); err != nil {
+		return nil, err
+	}
+	attribute.Options = req.Options
+	attribute.Required = req.Required
+	if err := u.attributeRepository.Edit(ctx, attribute); err != nil {
+		return nil, err
+	}
+	return attribute, nil
+}
+
+func (u *categoryAttributeUsecase) DeleteAttribute(ctx context.Context, categoryID int, id int) error {
+	if categoryID <= 0 || id <= 0 {
+		return errors.New("invalid ID")
+	}
+	return u.attributeRepository.Delete(ctx, categoryID, id)
+}
+
+// checkAttributeOptions requires options for enum attributes and rejects
+// them for the other types.
+func (u *categoryAttributeUsecase) checkAttributeOptions(ctx context.Context, attributeType string, options []string) error {
+	if attributeType == domain.AttributeEnum && len(options) == 0 {
+		return &domain.ValidationError{Fields: map[string]string{"options": u.validator.Message(ctx, "options_required")}}
+	}
+	if attributeType != domain.AttributeEnum && len(options) > 0 {
+		return &domain.ValidationError{Fields: map[string]string{"options": u.validator.Message(ctx, "options_excluded")}}
+	}
+	return nil
+}
@@ -3,29 +3,129 @@ package usecase
 import (
 	"context"
 	"errors"
+	"strconv"
+	"strings"
 	"test-elabram/internal/domain"
 	"test-elabram/internal/dto"
 )
 
 type categoryUsecase struct {
-	categoryRepo domain.CategoryRepository
+	categoryRepo    domain.CategoryRepository
+	validator       domain.RequestValidator
+	translationRepo domain.TranslationRepository
 }
 
-func NewCategoryUsecase(categoryRepo domain.CategoryRepository) domain.CategoryUsecase {
+func NewCategoryUsecase(categoryRepo domain.CategoryRepository, validator domain.RequestValidator, translationRepo domain.TranslationRepository) domain.CategoryUsecase {
 	return &categoryUsecase{
-		categoryRepo: categoryRepo,
+		categoryRepo:    categoryRepo,
+		validator:       validator,
+		translationRepo: translationRepo,
 	}
 }
 
-func (u *categoryUsecase) GetAllCategories(ctx context.Context) ([]domain.Category, error) {
-	return u.categoryRepo.GetAll(ctx)
+// GetAllCategories lists the categories with the fields in proj.
+func (u *categoryUsecase) GetAllCategories(ctx context.Context, proj dto.Projection) ([]domain.Category, error) {
+	categories, err := u.categoryRepo.GetAll(ctx, proj.Columns("id")...)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(ctx, u.translationRepo, pointers(categories)...); err != nil {
+		return nil, err
+	}
+	return categories, nil
 }
 
-func (u *categoryUsecase) GetCategoryByID(ctx context.Context, id int) (*domain.Category, error) {
+func (u *categoryUsecase) GetCategoryByID(ctx context.Context, id int, proj dto.Projection) (*domain.Category, error) {
 	if id <= 0 {
 		return nil, errors.New("invalid ID")
 	}
-	return u.categoryRepo.GetByID(ctx, id)
+	category, err := u.categoryRepo.GetByID(ctx, id, proj.Columns("id")...)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(ctx, u.translationRepo, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// GetCategoryBySlug resolves current and retired slugs alike; the returned
+// category always carries its current slug.
+func (u *categoryUsecase) GetCategoryBySlug(ctx context.Context, slug string, proj dto.Projection) (*domain.Category, error) {
+	if slug == "" {
+		return nil, errors.New("invalid slug")
+	}
+	category, err := u.categoryRepo.GetBySlug(ctx, slug, proj.Columns("id", "slug")...)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(ctx, u.translationRepo, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (u *categoryUsecase) GetCategoryTree(ctx context.Context) ([]*dto.CategoryTreeNode, error) {
+	categories, err := u.categoryRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(ctx, u.translationRepo, pointers(categories)...); err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories, nil), nil
+}
+
+func (u *categoryUsecase) GetCategorySubtree(ctx context.Context, id int) (*dto.CategoryTreeNode, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	category, err := u.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := u.categoryRepo.GetSubtree(ctx, category.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(ctx, u.translationRepo, pointers(categories)...); err != nil {
+		return nil, err
+	}
+
+	roots := buildCategoryTree(categories, &category.ID)
+	if len(roots) == 0 {
+		return nil, errors.New("category not found")
+	}
+	return roots[0], nil
+}
+
+// GetCategoryBreadcrumbs returns the ancestors of the category from the root
+// down, ending with the category itself.
+func (u *categoryUsecase) GetCategoryBreadcrumbs(ctx context.Context, id int) ([]domain.Category, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	category, err := u.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	for _, part := range strings.Split(strings.Trim(category.Path, "/"), "/") {
+		if ancestorID, err := strconv.ParseUint(part, 10, 64); err == nil {
+			ids = append(ids, uint(ancestorID))
+		}
+	}
+	// Paths sort parents before children, so the repository order is
+	// already root first.
+	breadcrumbs, err := u.categoryRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if err := localizeCategories(ctx, u.translationRepo, pointers(breadcrumbs)...); err != nil {
+		return nil, err
+	}
+	return breadcrumbs, nil
 }
 
 func (u *categoryUsecase) CreateCategory(ctx context.Context, category *domain.Category) error {
@@ -35,25 +135,102 @@ func (u *categoryUsecase) CreateCategory(ctx context.Context, category *domain.C
 	return u.categoryRepo.Create(ctx, category)
 }
 
-func (u *categoryUsecase) EditCategory(ctx context.Context, id int, category *dto.UpdateCategoryRequest) (*domain.Category, error) {
+func (u *categoryUsecase) ReplaceCategory(ctx context.Context, id int, req *dto.ReplaceCategoryRequest) (*domain.Category, error) {
+	existingCategory, err := u.categoryRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return u.replaceCategory(ctx, existingCategory, req)
+}
+
+// PatchCategory applies the patch to the category's ReplaceCategoryRequest
+// representation and validates the result before saving it.
+func (u *categoryUsecase) PatchCategory(ctx context.Context, id int, patch domain.Patch) (*domain.Category, error) {
 	existingCategory, err := u.categoryRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	if category.Name != "" {
-		existingCategory.Name = category.Name
+	current := dto.ReplaceCategoryRequest{
+		Name:             existingCategory.Name,
+		Description:      existingCategory.Description,
+		ReorderThreshold: existingCategory.ReorderThreshold,
 	}
-	if category.Description != "" {
-		existingCategory.Description = category.Description
+	var req dto.ReplaceCategoryRequest
+	if err := applyPatch(patch, current, &req); err != nil {
+		return nil, err
+	}
+	fieldErrors, err := u.validator.ValidateStruct(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	if len(fieldErrors) > 0 {
+		return nil, &domain.ValidationError{Fields: fieldErrors}
 	}
+	return u.replaceCategory(ctx, existingCategory, &req)
+}
+
+func (u *categoryUsecase) replaceCategory(ctx context.Context, category *domain.Category, req *dto.ReplaceCategoryRequest) (*domain.Category, error) {
+	category.Name = req.Name
+	category.Description = req.Description
+	category.ReorderThreshold = req.ReorderThreshold
 
-	if err := u.categoryRepo.Edit(ctx, existingCategory); err != nil {
+	if err := u.categoryRepo.Edit(ctx, category); err != nil {
 		return nil, err
 	}
-	return existingCategory, nil
+	return category, nil
+}
+
+func (u *categoryUsecase) MoveCategory(ctx context.Context, id int, parentID *uint) (*domain.Category, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	if parentID != nil && *parentID == uint(id) {
+		return nil, domain.ErrCategoryCycle
+	}
+	return u.categoryRepo.Move(ctx, id, parentID)
 }
 
 func (u *categoryUsecase) DeleteCategory(ctx context.Context, id int) error {
+	hasChildren, err := u.categoryRepo.HasChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return domain.ErrCategoryHasChildren
+	}
 	return u.categoryRepo.Delete(ctx, id)
 }
+
+// buildCategoryTree nests categories sorted by path. With rootID set only
+// that category's subtree is returned; otherwise every top-level category
+// is a root.
+func buildCategoryTree(categories []domain.Category, rootID *uint) []*dto.CategoryTreeNode {
+	nodes := make(map[uint]*dto.CategoryTreeNode, len(categories))
+	roots := []*dto.CategoryTreeNode{}
+	for _, c := range categories {
+		node := &dto.CategoryTreeNode{
+			ID:          c.ID,
+			Name:        c.Name,
+			Slug:        c.Slug,
+			Description: c.Description,
+			ParentID:    c.ParentID,
+			Path:        c.Path,
+			Children:    []*dto.CategoryTreeNode{},
+		}
+		nodes[c.ID] = node
+
+		isRoot := c.ParentID == nil
+		if rootID != nil {
+			isRoot = c.ID == *rootID
+		}
+		if isRoot {
+			roots = append(roots, node)
+			continue
+		}
+		if parent, ok := nodes[*c.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+	return roots
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/locale"
+)
+
+// localizeProducts shows the names and descriptions of the products, and of
+// their loaded categories, in the locale of ctx. Each takes the first
+// translation along the locale's fallback chain; without one it keeps its
+// own text, which is in locale.Default.
+func localizeProducts(ctx context.Context, repo domain.TranslationRepository, products ...*domain.Product) error {
+	pending := make(map[uint][]*domain.Product, len(products))
+	var categories []*domain.Category
+	for _, p := range products {
+		pending[p.ID] = append(pending[p.ID], p)
+		if p.Category.ID != 0 {
+			categories = append(categories, &p.Category)
+		}
+	}
+
+	for _, l := range locale.Chain(domain.LocaleFromContext(ctx)) {
+		if l == locale.Default || len(pending) == 0 {
+			break
+		}
+		translations, err := repo.ProductTranslations(ctx, l, slices.Sorted(maps.Keys(pending)))
+		if err != nil {
+			return err
+		}
+		for id, t := range translations {
+			for _, p := range pending[id] {
+				p.Name = t.Name
+				p.Description = t.Description
+			}
+			delete(pending, id)
+		}
+	}
+	return localizeCategories(ctx, repo, categories...)
+}
+
+// localizeCategories is localizeProducts for categories.
+func localizeCategories(ctx context.Context, repo domain.TranslationRepository, categories ...*domain.Category) error {
+	pending := make(map[uint][]*domain.Category, len(categories))
+	for _, c := range categories {
+		pending[c.ID] = append(pending[c.ID], c)
+	}
+
+	for _, l := range locale.Chain(domain.LocaleFromContext(ctx)) {
+		if l == locale.Default || len(pending) == 0 {
+			break
+		}
+		translations, err := repo.CategoryTranslations(ctx, l, slices.Sorted(maps.Keys(pending)))
+		if err != nil {
+			return err
+		}
+		for id, t := range translations {
+			for _, c := range pending[id] {
+				c.Name = t.Name
+				c.Description = t.Description
+			}
+			delete(pending, id)
+		}
+	}
+	return nil
+}
+
+// pointers returns pointers to the elements of s, for the helpers above.
+func pointers[T any](s []T) []*T {
+	ptrs := make([]*T, len(s))
+	for i := range s {
+		ptrs[i] = &s[i]
+	}
+	return ptrs
+}
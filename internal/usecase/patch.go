@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"test-elabram/internal/domain"
+)
+
+// applyPatch runs patch against the JSON form of current and decodes the
+// result into out. Fields the representation does not have are rejected.
+func applyPatch(patch domain.Patch, current interface{}, out interface{}) error {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	patched, err := patch.Apply(doc)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(out); err != nil {
+		return fmt.Errorf("%w: patched document: %v", domain.ErrInvalidPatch, err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"test-elabram/internal/cache"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"time"
+)
+
+// localTimeLayouts are the accepted forms of a scheduled time without a UTC
+// offset, which is read in the store's time zone.
+var localTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+type priceChangeUsecase struct {
+	priceChangeRepository domain.PriceChangeRepository
+	cache                 *cache.RedisCache
+	location              *time.Location
+	validator             domain.RequestValidator
+	wake                  chan struct{}
+}
+
+// NewPriceChangeUsecase returns the price history usecase. Scheduled times
+// without a UTC offset are read in location, which is also the zone the
+// scheduler reports times in.
+func NewPriceChangeUsecase(priceChangeRepository domain.PriceChangeRepository, redisCache *cache.RedisCache, location *time.Location, validator domain.RequestValidator) domain.PriceChangeUsecase {
+	return &priceChangeUsecase{
+		priceChangeRepository: priceChangeRepository,
+		cache:                 redisCache,
+		location:              location,
+		validator:             validator,
+		wake:                  make(chan struct{}, 1),
+	}
+}
+
+func (u *priceChangeUsecase) GetPriceHistory(ctx context.Context, productID int, pq dto.PaginationQuery) (*dto.PaginatedResponse, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	if pq.Page <= 0 {
+		pq.Page = 1
+	}
+	if pq.Limit <= 0 {
+		pq.Limit = 10
+	}
+	if pq.Limit > 100 {
+		pq.Limit = 100
+	}
+
+	changes, total, err := u.priceChangeRepository.GetByProductID(ctx, productID, pq)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PaginatedResponse{
+		Data:       changes,
+		Page:       pq.Page,
+		Limit:      pq.Limit,
+		TotalItems: total,
+		TotalPages: int(math.Ceil(float64(total) / float64(pq.Limit))),
+	}, nil
+}
+
+// SchedulePriceChange records a price change that the scheduler applies to
+// the product once it takes effect.
+func (u *priceChangeUsecase) SchedulePriceChange(ctx context.Context, productID int, req *dto.PriceChangeRequest) (*domain.PriceChange, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	effectiveFrom, err := u.parseTime(req.EffectiveFrom)
+	if err != nil {
+		return nil, &domain.ValidationError{Fields: map[string]string{"effective_from": u.validator.Message(ctx, "schedule_time")}}
+	}
+	if !effectiveFrom.After(time.Now()) {
+		return nil, &domain.ValidationError{Fields: map[string]string{"effective_from": u.validator.Message(ctx, "future")}}
+	}
+
+	change := &domain.PriceChange{
+		ProductID:     uint(productID),
+		Price:         req.Price.Money(),
+		EffectiveFrom: effectiveFrom,
+		Reason:        strings.TrimSpace(req.Reason),
+	}
+	if err := u.priceChangeRepository.Schedule(ctx, change); err != nil {
+		return nil, err
+	}
+
+	// Let the scheduler wait for this change if it is the next one.
+	select {
+	case u.wake <- struct{}{}:
+	default:
+	}
+	return change, nil
+}
+
+func (u *priceChangeUsecase) CancelPriceChange(ctx context.Context, productID int, changeID int) error {
+	if productID <= 0 || changeID <= 0 {
+		return errors.New("invalid ID")
+	}
+	return u.priceChangeRepository.Cancel(ctx, productID, changeID)
+}
+
+// ApplyDuePriceChanges applies the scheduled changes that took effect and
+// returns the number of products whose price was updated.
+func (u *priceChangeUsecase) ApplyDuePriceChanges(ctx context.Context) (int, error) {
+	productIDs, err := u.priceChangeRepository.ApplyDue(ctx, time.Now().In(u.location))
+	if err != nil {
+		return 0, err
+	}
+	if len(productIDs) > 0 && u.cache != nil && u.cache.IsAvailable() {
+		if err := u.cache.Delete(ctx, productCacheKey["report"]); err != nil {
+			log.Printf("[CACHE] Failed to invalidate report cache: %v", err)
+		}
+	}
+	return len(productIDs), nil
+}
+
+// RunScheduler applies scheduled price changes until ctx is done. It sleeps
+// until the next pending change takes effect, but no longer than interval,
+// so changes scheduled by other instances are picked up too.
+func (u *priceChangeUsecase) RunScheduler(ctx context.Context, interval time.Duration) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-u.wake:
+		case <-timer.C:
+			applied, err := u.ApplyDuePriceChanges(ctx)
+			if err != nil {
+				log.Printf("[PRICE] Failed to apply scheduled price changes: %v", err)
+			} else if applied > 0 {
+				log.Printf("[PRICE] Applied scheduled prices to %d products", applied)
+			}
+		}
+
+		wait := interval
+		next, err := u.priceChangeRepository.NextScheduled(ctx)
+		if err != nil {
+			log.Printf("[PRICE] Failed to look up the next scheduled price change: %v", err)
+		} else if next != nil {
+			wait = min(max(time.Until(*next), 0), interval)
+		}
+		timer.Reset(wait)
+	}
+}
+
+// parseTime reads s as an RFC 3339 time, or as a local time in the store's
+// time zone.
+func (u *priceChangeUsecase) parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(u.location), nil
+	}
+	var err error
+	for _, layout := range localTimeLayouts {
+		var t time.Time
+		if t, err = time.ParseInLocation(layout, s, u.location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
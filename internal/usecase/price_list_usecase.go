@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/money"
+)
+
+type priceListUsecase struct {
+	priceListRepository domain.PriceListRepository
+	validator           domain.RequestValidator
+}
+
+func NewPriceListUsecase(priceListRepository domain.PriceListRepository, validator domain.RequestValidator) domain.PriceListUsecase {
+	return &priceListUsecase{
+		priceListRepository: priceListRepository,
+		validator:           validator,
+	}
+}
+
+func (u *priceListUsecase) GetAllPriceLists(ctx context.Context) ([]domain.PriceList, error) {
+	return u.priceListRepository.GetAll(ctx)
+}
+
+func (u *priceListUsecase) GetPriceListByID(ctx context.Context, id int) (*domain.PriceList, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.priceListRepository.GetByID(ctx, id)
+}
+
+func (u *priceListUsecase) CreatePriceList(ctx context.Context, req *dto.PriceListRequest) (*domain.PriceList, error) {
+	priceList := domain.PriceList{
+		Code:     strings.TrimSpace(req.Code),
+		Name:     req.Name,
+		Currency: req.Currency,
+	}
+	if err := u.priceListRepository.Create(ctx, &priceList); err != nil {
+		return nil, err
+	}
+	return &priceList, nil
+}
+
+func (u *priceListUsecase) DeletePriceList(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid ID")
+	}
+	return u.priceListRepository.Delete(ctx, id)
+}
+
+func (u *priceListUsecase) GetPriceListPrices(ctx context.Context, id int, pq dto.PaginationQuery) (*dto.PaginatedResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	if pq.Page <= 0 {
+		pq.Page = 1
+	}
+	if pq.Limit <= 0 {
+		pq.Limit = 10
+	}
+	if pq.Limit > 100 {
+		pq.Limit = 100
+	}
+
+	items, total, err := u.priceListRepository.GetPrices(ctx, id, pq)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PaginatedResponse{
+		Data:       items,
+		Page:       pq.Page,
+		Limit:      pq.Limit,
+		TotalItems: total,
+		TotalPages: int(math.Ceil(float64(total) / float64(pq.Limit))),
+	}, nil
+}
+
+// SetPriceListPrice overrides the price of a product in a price list.
+func (u *priceListUsecase) SetPriceListPrice(ctx context.Context, id int, productID int, req *dto.PriceListPriceRequest) (*domain.PriceListItem, error) {
+	if id <= 0 || productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	item := domain.PriceListItem{
+		PriceListID: uint(id),
+		ProductID:   uint(productID),
+		Price:       req.Price.Money(),
+	}
+	if err := u.priceListRepository.SetPrice(ctx, &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (u *priceListUsecase) DeletePriceListPrice(ctx context.Context, id int, productID int) error {
+	if id <= 0 || productID <= 0 {
+		return errors.New("invalid ID")
+	}
+	return u.priceListRepository.DeletePrice(ctx, id, productID)
+}
+
+func (u *priceListUsecase) GetExchangeRates(ctx context.Context) ([]domain.ExchangeRate, error) {
+	return u.priceListRepository.GetExchangeRates(ctx)
+}
+
+// SetExchangeRate sets the rate converting base to quote. The reverse
+// direction is a rate of its own, so conversions both ways are explicit.
+func (u *priceListUsecase) SetExchangeRate(ctx context.Context, base string, quote string, req *dto.ExchangeRateRequest) (*domain.ExchangeRate, error) {
+	if err := u.validateCurrencyPair(ctx, base, quote); err != nil {
+		return nil, err
+	}
+	rate := domain.ExchangeRate{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          req.Rate,
+	}
+	if err := u.priceListRepository.SetExchangeRate(ctx, &rate); err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (u *priceListUsecase) DeleteExchangeRate(ctx context.Context, base string, quote string) error {
+	if err := u.validateCurrencyPair(ctx, base, quote); err != nil {
+		return err
+	}
+	return u.priceListRepository.DeleteExchangeRate(ctx, base, quote)
+}
+
+// validateCurrencyPair checks the currencies of an exchange rate taken from
+// the URL.
+func (u *priceListUsecase) validateCurrencyPair(ctx context.Context, base string, quote string) error {
+	fields := map[string]string{}
+	for field, code := range map[string]string{"base": base, "quote": quote} {
+		if _, ok := money.Exponent(code); !ok {
+			fields[field] = u.validator.Message(ctx, "oneof", strings.Join(money.Currencies(), ", "))
+		}
+	}
+	if len(fields) == 0 && base == quote {
+		fields["quote"] = u.validator.Message(ctx, "currency_pair")
+	}
+	if len(fields) > 0 {
+		return &domain.ValidationError{Fields: fields}
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+)
+
+// batchItem is an operation that passed decoding and validation.
+type batchItem struct {
+	index  int
+	op     string
+	id     int
+	create *dto.CreateProductRequest
+	update *dto.UpdateProductRequest
+}
+
+// BatchProducts runs a list of create, update and delete operations. In
+// atomic mode every operation shares one transaction and nothing is written
+// unless all of them succeed; in best-effort mode each operation commits on
+// its own. The report cache is invalidated once, after the whole batch.
+func (u *productUsecase) BatchProducts(ctx context.Context, req *dto.BatchProductRequest) (*dto.BatchProductResponse, error) {
+	mode := req.Mode
+	if mode == "" {
+		mode = dto.BatchModeAtomic
+	}
+
+	resp := &dto.BatchProductResponse{
+		Mode:    mode,
+		Results: make([]dto.BatchProductResult, len(req.Operations)),
+	}
+
+	var items []batchItem
+	for i, op := range req.Operations {
+		resp.Results[i] = dto.BatchProductResult{Index: i, Op: op.Op, ID: uint(max(op.ID, 0))}
+		item, fieldErrors, err := u.prepareBatchItem(ctx, i, op)
+		if err != nil || len(fieldErrors) > 0 {
+			resp.Results[i].Status = dto.BatchStatusFailed
+			resp.Results[i].Errors = fieldErrors
+			if err != nil {
+				resp.Results[i].Error = err.Error()
+			}
+			continue
+		}
+		items = append(items, item)
+	}
+
+	// removed collects the media of deleted products, whose files are
+	// removed once their deletion is committed.
+	var removed []domain.ProductMedia
+	if mode == dto.BatchModeAtomic {
+		removed = u.runAtomicBatch(ctx, items, resp)
+	} else {
+		for _, item := range items {
+			var media []domain.ProductMedia
+			err := u.productRepository.Transaction(ctx, func(repo domain.ProductRepository) error {
+				return u.runBatchItem(ctx, repo, item, &resp.Results[item.index], &media)
+			})
+			if err == nil {
+				removed = append(removed, media...)
+			} else {
+				resp.Results[item.index].Status = dto.BatchStatusFailed
+				resp.Results[item.index].Error = err.Error()
+				resp.Results[item.index].Data = nil
+			}
+		}
+	}
+
+	for _, result := range resp.Results {
+		switch result.Status {
+		case dto.BatchStatusOK:
+			resp.Succeeded++
+		case dto.BatchStatusFailed:
+			resp.Failed++
+		}
+	}
+	if resp.Succeeded > 0 {
+		u.invalidateReportCache(ctx, productCacheKey["report"])
+		u.deleteMediaFiles(ctx, removed)
+
+		var changed []uint
+		for _, result := range resp.Results {
+			if result.Status == dto.BatchStatusOK && result.Op != dto.BatchOpDelete {
+				changed = append(changed, result.ID)
+			}
+			if product, ok := result.Data.(*domain.Product); ok {
+				u.setMediaURLs(product)
+			}
+		}
+		u.stockObserver.StockChanged(changed...)
+	}
+	return resp, nil
+}
+
+// runAtomicBatch returns the media of the deleted products once the batch
+// has committed.
+func (u *productUsecase) runAtomicBatch(ctx context.Context, items []batchItem, resp *dto.BatchProductResponse) []domain.ProductMedia {
+	invalid := len(items) < len(resp.Results)
+	if invalid {
+		for _, item := range items {
+			resp.Results[item.index].Status = dto.BatchStatusSkipped
+		}
+		return nil
+	}
+
+	failed := -1
+	var removed []domain.ProductMedia
+	err := u.productRepository.Transaction(ctx, func(repo domain.ProductRepository) error {
+		for _, item := range items {
+			if err := u.runBatchItem(ctx, repo, item, &resp.Results[item.index], &removed); err != nil {
+				failed = item.index
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		return removed
+	}
+
+	for i := range resp.Results {
+		result := &resp.Results[i]
+		switch {
+		case i == failed:
+			result.Status = dto.BatchStatusFailed
+			result.Error = err.Error()
+		case result.Status == dto.BatchStatusOK:
+			result.Status = dto.BatchStatusRolledBack
+		default:
+			result.Status = dto.BatchStatusSkipped
+		}
+		result.Data = nil
+	}
+	if failed < 0 {
+		// The commit itself failed.
+		for i := range resp.Results {
+			resp.Results[i].Status = dto.BatchStatusFailed
+			resp.Results[i].Error = err.Error()
+		}
+	}
+	return nil
+}
+
+// prepareBatchItem decodes the payload for the operation and validates it with
+// the same rules as the single-item endpoints.
+func (u *productUsecase) prepareBatchItem(ctx context.Context, index int, op dto.BatchProductOperation) (batchItem, map[string]string, error) {
+	item := batchItem{index: index, op: op.Op, id: op.ID}
+
+	switch op.Op {
+	case dto.BatchOpCreate:
+		item.create = &dto.CreateProductRequest{}
+		if err := decodeBatchData(op.Data, item.create); err != nil {
+			return item, nil, err
+		}
+		fieldErrors, err := u.validator.ValidateStruct(ctx, item.create)
+		return item, fieldErrors, err
+	case dto.BatchOpUpdate:
+		if op.ID <= 0 {
+			return item, nil, errors.New("invalid ID")
+		}
+		item.update = &dto.UpdateProductRequest{}
+		if err := decodeBatchData(op.Data, item.update); err != nil {
+			return item, nil, err
+		}
+		fieldErrors, err := u.validator.ValidateStruct(ctx, item.update)
+		return item, fieldErrors, err
+	case dto.BatchOpDelete:
+		if op.ID <= 0 {
+			return item, nil, errors.New("invalid ID")
+		}
+		return item, nil, nil
+	default:
+		return item, nil, fmt.Errorf("unknown op %q, use create, update or delete", op.Op)
+	}
+}
+
+// runBatchItem runs one operation, adding the media of a deleted product to
+// removed. Tags and attributes that fail the checks of the single-item
+// endpoints fail the operation with their field errors.
+func (u *productUsecase) runBatchItem(ctx context.Context, repo domain.ProductRepository, item batchItem, result *dto.BatchProductResult, removed *[]domain.ProductMedia) error {
+	switch item.op {
+	case dto.BatchOpCreate:
+		product := domain.Product{
+			Name:             item.create.Name,
+			Description:      item.create.Description,
+			Price:            item.create.Price.Money(),
+			StockQuantity:    item.create.StockQuantity,
+			IsActive:         item.create.IsActive,
+			CategoryID:       item.create.CategoryID,
+			ReorderThreshold: item.create.ReorderThreshold,
+			Tags:             domain.TagRefs(item.create.Tags),
+			Attributes:       item.create.Attributes,
+		}
+		if err := u.prepareBatchProduct(ctx, &product, result); err != nil {
+			return err
+		}
+		if err := u.writeBatchError(ctx, repo.Create(ctx, &product), result); err != nil {
+			return err
+		}
+		result.ID = product.ID
+		result.Data = product
+	case dto.BatchOpUpdate:
+		product, err := repo.GetByID(ctx, item.id)
+		if err != nil {
+			return err
+		}
+		applyProductUpdate(product, item.update)
+		if err := u.prepareBatchProduct(ctx, product, result); err != nil {
+			return err
+		}
+		if err := u.writeBatchError(ctx, repo.Edit(ctx, product), result); err != nil {
+			return err
+		}
+		result.Data = product
+	case dto.BatchOpDelete:
+		if _, err := repo.GetByID(ctx, item.id); err != nil {
+			return err
+		}
+		media, err := repo.Delete(ctx, item.id)
+		if err != nil {
+			return err
+		}
+		*removed = append(*removed, media...)
+	}
+	result.Status = dto.BatchStatusOK
+	return nil
+}
+
+func (u *productUsecase) prepareBatchProduct(ctx context.Context, product *domain.Product, result *dto.BatchProductResult) error {
+	err := u.prepareProduct(ctx, product)
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		result.Errors = validationErr.Fields
+	}
+	return err
+}
+
+// writeBatchError is writeError for a batch operation, whose field errors
+// go into its result.
+func (u *productUsecase) writeBatchError(ctx context.Context, err error, result *dto.BatchProductResult) error {
+	err = u.writeError(ctx, err)
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		result.Errors = validationErr.Fields
+	}
+	return err
+}
+
+func decodeBatchData(data json.RawMessage, v interface{}) error {
+	if len(data) == 0 {
+		return errors.New("data is required")
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid data: %w", err)
+	}
+	return nil
+}
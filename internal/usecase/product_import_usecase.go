@@ -0,0 +1,352 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"test-elabram/internal/cache"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/money"
+	"time"
+)
+
+const (
+	importBatchSize = 500
+	importJobTTL    = time.Hour
+)
+
+type productImportUsecase struct {
+	productRepository   domain.ProductRepository
+	categoryRepository  domain.CategoryRepository
+	attributeRepository domain.CategoryAttributeRepository
+	cache               *cache.RedisCache
+	validator           domain.RequestValidator
+
+	mu   sync.Mutex
+	jobs map[string]*dto.ImportJob
+}
+
+func NewProductImportUsecase(productRepository domain.ProductRepository, categoryRepository domain.CategoryRepository, attributeRepository domain.CategoryAttributeRepository, redisCache *cache.RedisCache, validator domain.RequestValidator) domain.ProductImportUsecase {
+	return &productImportUsecase{
+		productRepository:   productRepository,
+		categoryRepository:  categoryRepository,
+		attributeRepository: attributeRepository,
+		cache:               redisCache,
+		validator:           validator,
+		jobs:                make(map[string]*dto.ImportJob),
+	}
+}
+
+// StartImport registers a job for the rows and processes it in the
+// background. The returned snapshot can be polled with GetImportJob.
+func (u *productImportUsecase) StartImport(ctx context.Context, rows []dto.ImportProductRow, opts dto.ImportOptions) (*dto.ImportJob, error) {
+	id, err := newImportJobID()
+	if err != nil {
+		return nil, err
+	}
+
+	job := &dto.ImportJob{
+		ID:        id,
+		Status:    dto.ImportStatusPending,
+		DryRun:    opts.DryRun,
+		TotalRows: len(rows),
+		Errors:    []dto.ImportRowError{},
+		CreatedAt: time.Now(),
+	}
+
+	u.mu.Lock()
+	u.pruneJobs()
+	u.jobs[id] = job
+	snapshot := snapshotJob(job)
+	u.mu.Unlock()
+
+	// The request context ends with the response, so the job gets its own.
+	go u.run(context.WithoutCancel(ctx), job, rows)
+
+	return snapshot, nil
+}
+
+func (u *productImportUsecase) GetImportJob(ctx context.Context, id string) (*dto.ImportJob, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	job, ok := u.jobs[id]
+	if !ok {
+		return nil, domain.ErrImportJobNotFound
+	}
+	return snapshotJob(job), nil
+}
+
+func (u *productImportUsecase) run(ctx context.Context, job *dto.ImportJob, rows []dto.ImportProductRow) {
+	u.update(job, func(j *dto.ImportJob) { j.Status = dto.ImportStatusRunning })
+
+	categories, err := u.categoryRepository.GetAll(ctx)
+	if err != nil {
+		u.fail(job, err)
+		return
+	}
+	byID := make(map[uint]bool, len(categories))
+	byName := make(map[string]uint, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = true
+		byName[strings.ToLower(strings.TrimSpace(c.Name))] = c.ID
+	}
+
+	// The attributes that apply to the categories of the rows, looked up once
+	// per category.
+	schemas := make(map[uint][]domain.CategoryAttribute)
+
+	written := false
+	for start := 0; start < len(rows); start += importBatchSize {
+		end := min(start+importBatchSize, len(rows))
+		batch := rows[start:end]
+
+		var rowErrors []dto.ImportRowError
+		var products []*domain.Product
+		var productRows []int
+
+		var ids []uint
+		for _, row := range batch {
+			if row.ID != nil {
+				ids = append(ids, *row.ID)
+			}
+		}
+		existing, err := u.productRepository.GetExisting(ctx, ids)
+		if err != nil {
+			u.fail(job, err)
+			return
+		}
+
+		for _, row := range batch {
+			errs, err := u.validateRow(ctx, row, byID, byName, existing, schemas)
+			if err != nil {
+				u.fail(job, err)
+				return
+			}
+			if len(errs) > 0 {
+				rowErrors = append(rowErrors, dto.ImportRowError{Row: row.Row, Errors: errs})
+				continue
+			}
+			products = append(products, rowToProduct(row, byName))
+			productRows = append(productRows, row.Row)
+		}
+
+		created, updated := 0, 0
+		if !job.DryRun && len(products) > 0 {
+			created, updated, err = u.productRepository.UpsertBatch(ctx, products)
+			if err != nil {
+				for _, r := range productRows {
+					rowErrors = append(rowErrors, dto.ImportRowError{
+						Row:    r,
+						Errors: map[string]string{"batch": u.validator.Message(ctx, "batch_rolled_back", err.Error())},
+					})
+				}
+			} else {
+				written = true
+			}
+		}
+
+		u.update(job, func(j *dto.ImportJob) {
+			j.ProcessedRows += len(batch)
+			j.Created += created
+			j.Updated += updated
+			j.Failed += len(rowErrors)
+			j.Errors = append(j.Errors, rowErrors...)
+		})
+	}
+
+	if written {
+		u.invalidateReportCache(ctx)
+	}
+
+	u.update(job, func(j *dto.ImportJob) {
+		now := time.Now()
+		j.Status = dto.ImportStatusCompleted
+		j.FinishedAt = &now
+	})
+}
+
+// validateRow resolves the category and applies the CreateProductRequest
+// rules to the row, then the rules on stored data and the attributes of the
+// category. A row without an ID updates the product of its category with
+// the same name, if any, so its name is never taken. A row that updates a
+// product may only repeat its stock, which changes through the ledger.
+func (u *productImportUsecase) validateRow(ctx context.Context, row dto.ImportProductRow, byID map[uint]bool, byName map[string]uint, existing map[uint]*domain.Product, schemas map[uint][]domain.CategoryAttribute) (map[string]string, error) {
+	errs := make(map[string]string)
+	for field, problem := range row.ParseErrors {
+		errs[field] = u.validator.Message(ctx, problem.Key, problem.Params...)
+	}
+
+	var categoryID uint
+	switch {
+	case row.CategoryID != nil:
+		categoryID = *row.CategoryID
+		if categoryID > 0 && !byID[categoryID] {
+			errs["category_id"] = u.validator.Message(ctx, "category_exists")
+		}
+	case strings.TrimSpace(row.Category) != "":
+		id, ok := byName[strings.ToLower(strings.TrimSpace(row.Category))]
+		if !ok {
+			errs["category"] = u.validator.Message(ctx, "category_exists")
+		}
+		categoryID = id
+	}
+
+	if row.ID != nil && existing[*row.ID] == nil {
+		errs["id"] = u.validator.Message(ctx, "product_exists")
+	}
+
+	req := dto.CreateProductRequest{
+		Name:          row.Name,
+		Description:   row.Description,
+		Price:         rowPrice(row),
+		StockQuantity: rowStock(row),
+		IsActive:      row.IsActive,
+		CategoryID:    categoryID,
+	}
+	fieldErrors, err := u.validator.ValidateStruct(ctx, &req)
+	if err != nil {
+		return nil, err
+	}
+	for field, msg := range fieldErrors {
+		if _, ok := errs[field]; !ok {
+			errs[field] = msg
+		}
+	}
+	if len(errs) > 0 {
+		return errs, nil
+	}
+
+	product := rowToProduct(row, byName)
+	stored := existing[product.ID]
+	if row.ID != nil {
+		errs, err = u.validator.ValidateStruct(ctx, product)
+		if err != nil || len(errs) > 0 {
+			return errs, err
+		}
+	} else {
+		stored, err = u.productRepository.GetByCategoryAndName(ctx, product.CategoryID, product.Name)
+		if errors.Is(err, domain.ErrProductNotFound) {
+			stored, err = nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if stored != nil && row.StockQuantity != nil && *row.StockQuantity != stored.StockQuantity {
+		return map[string]string{"stock_quantity": u.validator.Message(ctx, "ledger")}, nil
+	}
+	return u.rowAttributeErrors(ctx, product, stored, schemas)
+}
+
+// rowAttributeErrors checks the attributes a row's product ends up with
+// against its category. Imports carry no attributes: a created product has
+// none and an updated one, stored, keeps its own, which only need a check
+// when the row moves it to another category.
+func (u *productImportUsecase) rowAttributeErrors(ctx context.Context, product *domain.Product, stored *domain.Product, schemas map[uint][]domain.CategoryAttribute) (map[string]string, error) {
+	values := domain.ProductAttributes{}
+	if stored != nil {
+		if stored.CategoryID == product.CategoryID {
+			return nil, nil
+		}
+		values = stored.Attributes
+	}
+
+	schema, ok := schemas[product.CategoryID]
+	if !ok {
+		var err error
+		schema, err = u.attributeRepository.GetEffective(ctx, product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		schemas[product.CategoryID] = schema
+	}
+	return attributeErrors(ctx, u.validator, schema, values), nil
+}
+
+func rowToProduct(row dto.ImportProductRow, byName map[string]uint) *domain.Product {
+	product := &domain.Product{
+		Name:          row.Name,
+		Description:   row.Description,
+		Price:         rowPrice(row).Money(),
+		StockQuantity: rowStock(row),
+		IsActive:      row.IsActive,
+	}
+	if row.ID != nil {
+		product.ID = *row.ID
+	}
+	if row.CategoryID != nil {
+		product.CategoryID = *row.CategoryID
+	} else {
+		product.CategoryID = byName[strings.ToLower(strings.TrimSpace(row.Category))]
+	}
+	return product
+}
+
+// rowStock returns the opening stock of a row, 0 when it has none.
+func rowStock(row dto.ImportProductRow) int {
+	if row.StockQuantity == nil {
+		return 0
+	}
+	return *row.StockQuantity
+}
+
+// rowPrice returns the price of a row, in the default currency when the row
+// does not name one.
+func rowPrice(row dto.ImportProductRow) dto.MoneyRequest {
+	return dto.MoneyRequest{Amount: row.Price.String(), Currency: cmp.Or(row.Currency, money.DefaultCurrency)}
+}
+
+func (u *productImportUsecase) update(job *dto.ImportJob, fn func(j *dto.ImportJob)) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	fn(job)
+}
+
+func (u *productImportUsecase) fail(job *dto.ImportJob, err error) {
+	log.Printf("[IMPORT] Job %s failed: %v", job.ID, err)
+	u.update(job, func(j *dto.ImportJob) {
+		now := time.Now()
+		j.Status = dto.ImportStatusFailed
+		j.Message = err.Error()
+		j.FinishedAt = &now
+	})
+}
+
+// pruneJobs drops finished jobs older than importJobTTL. Callers hold u.mu.
+func (u *productImportUsecase) pruneJobs() {
+	for id, job := range u.jobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > importJobTTL {
+			delete(u.jobs, id)
+		}
+	}
+}
+
+func (u *productImportUsecase) invalidateReportCache(ctx context.Context) {
+	if u.cache != nil && u.cache.IsAvailable() {
+		if err := u.cache.Delete(ctx, productCacheKey["report"]); err != nil {
+			log.Printf("[CACHE] Failed to invalidate report cache: %v", err)
+		}
+	}
+}
+
+func snapshotJob(job *dto.ImportJob) *dto.ImportJob {
+	snapshot := *job
+	snapshot.Errors = make([]dto.ImportRowError, len(job.Errors))
+	copy(snapshot.Errors, job.Errors)
+	return &snapshot
+}
+
+func newImportJobID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
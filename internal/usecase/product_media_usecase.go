@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/media"
+)
+
+type productMediaUsecase struct {
+	mediaRepository domain.ProductMediaRepository
+	storage         domain.MediaStorage
+}
+
+func NewProductMediaUsecase(mediaRepository domain.ProductMediaRepository, storage domain.MediaStorage) domain.ProductMediaUsecase {
+	return &productMediaUsecase{
+		mediaRepository: mediaRepository,
+		storage:         storage,
+	}
+}
+
+func (u *productMediaUsecase) GetMedia(ctx context.Context, productID int) ([]domain.ProductMedia, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	list, err := u.mediaRepository.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	setMediaURLs(u.storage, list)
+	return list, nil
+}
+
+// UploadMedia checks the image, stores it and its thumbnail, and appends it
+// to the product's media. Files stored for an upload that then fails to be
+// recorded are removed again.
+func (u *productMediaUsecase) UploadMedia(ctx context.Context, productID int, file io.Reader, primary bool) (*domain.ProductMedia, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	img, err := media.Process(file)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := newMediaName()
+	if err != nil {
+		return nil, err
+	}
+	item := domain.ProductMedia{
+		ProductID:    uint(productID),
+		StorageKey:   fmt.Sprintf("products/%d/%s.%s", productID, name, img.Ext),
+		ThumbnailKey: fmt.Sprintf("products/%d/%s_thumb.%s", productID, name, img.ThumbnailExt),
+		ContentType:  img.ContentType,
+		Size:         int64(len(img.Data)),
+		Width:        img.Width,
+		Height:       img.Height,
+		IsPrimary:    primary,
+	}
+
+	if err := u.storage.Put(ctx, item.StorageKey, bytes.NewReader(img.Data)); err != nil {
+		return nil, err
+	}
+	if err := u.storage.Put(ctx, item.ThumbnailKey, bytes.NewReader(img.Thumbnail)); err != nil {
+		deleteMediaFiles(ctx, u.storage, []domain.ProductMedia{item})
+		return nil, err
+	}
+	if err := u.mediaRepository.Create(ctx, &item); err != nil {
+		deleteMediaFiles(ctx, u.storage, []domain.ProductMedia{item})
+		return nil, err
+	}
+	setMediaURL(u.storage, &item)
+	return &item, nil
+}
+
+// ReorderMedia sets the display order of the product's media to ids and
+// returns them in that order.
+func (u *productMediaUsecase) ReorderMedia(ctx context.Context, productID int, ids []uint) ([]domain.ProductMedia, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	if err := u.mediaRepository.Reorder(ctx, productID, ids); err != nil {
+		return nil, err
+	}
+	return u.GetMedia(ctx, productID)
+}
+
+func (u *productMediaUsecase) SetPrimaryMedia(ctx context.Context, productID int, id int) (*domain.ProductMedia, error) {
+	if productID <= 0 || id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	if err := u.mediaRepository.SetPrimary(ctx, productID, id); err != nil {
+		return nil, err
+	}
+	item, err := u.mediaRepository.GetByID(ctx, productID, id)
+	if err != nil {
+		return nil, err
+	}
+	setMediaURL(u.storage, item)
+	return item, nil
+}
+
+// DeleteMedia removes the media and then its files.
+func (u *productMediaUsecase) DeleteMedia(ctx context.Context, productID int, id int) error {
+	if productID <= 0 || id <= 0 {
+		return errors.New("invalid ID")
+	}
+	item, err := u.mediaRepository.Delete(ctx, productID, id)
+	if err != nil {
+		return err
+	}
+	deleteMediaFiles(ctx, u.storage, []domain.ProductMedia{*item})
+	return nil
+}
+
+func (u *productMediaUsecase) OpenFile(ctx context.Context, key string) (io.ReadCloser, error) {
+	return u.storage.Open(ctx, key)
+}
+
+// setMediaURL sets where clients fetch the media and its thumbnail.
+func setMediaURL(storage domain.MediaStorage, item *domain.ProductMedia) {
+	item.URL = storage.URL(item.StorageKey)
+	item.ThumbnailURL = storage.URL(item.ThumbnailKey)
+}
+
+func setMediaURLs(storage domain.MediaStorage, items []domain.ProductMedia) {
+	for i := range items {
+		setMediaURL(storage, &items[i])
+	}
+}
+
+// deleteMediaFiles removes the files of the media. A file that cannot be
+// removed is logged and left behind.
+func deleteMediaFiles(ctx context.Context, storage domain.MediaStorage, items []domain.ProductMedia) {
+	for _, item := range items {
+		for _, key := range []string{item.StorageKey, item.ThumbnailKey} {
+			if err := storage.Delete(ctx, key); err != nil {
+				log.Printf("[MEDIA] Failed to delete %s: %v", key, err)
+			}
+		}
+	}
+}
+
+func newMediaName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
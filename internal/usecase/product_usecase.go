@@ -4,37 +4,77 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
+	"maps"
 	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"test-elabram/internal/cache"
 	"test-elabram/internal/domain"
 	"test-elabram/internal/dto"
+	"test-elabram/internal/filter"
+	"test-elabram/internal/money"
 	"time"
 )
 
 const reportCacheTTL = 5 * time.Minute
 
+// Suggestions are requested on every keystroke, so they are cached briefly
+// and given up on rather than left to queue up behind a slow database.
+const (
+	suggestCacheTTL = 30 * time.Second
+	suggestTimeout  = 300 * time.Millisecond
+	suggestLimit    = 5
+)
+
 var productCacheKey = map[string]string{
-	"report": "product:report",
+	"report":  "product:report",
+	"suggest": "product:suggest:",
 }
 
 type productUsecase struct {
-	productRepository domain.ProductRepository
-	cache             *cache.RedisCache
+	productRepository   domain.ProductRepository
+	priceListRepository domain.PriceListRepository
+	cache               *cache.RedisCache
+	validator           domain.RequestValidator
+	stockObserver       domain.StockObserver
+	mediaStorage        domain.MediaStorage
+	tagRepository       domain.TagRepository
+	attributeRepository domain.CategoryAttributeRepository
+	translationRepo     domain.TranslationRepository
 }
 
-func NewProductUsecase(productRepository domain.ProductRepository, redisCache *cache.RedisCache) domain.ProductUsecase {
+func NewProductUsecase(productRepository domain.ProductRepository, priceListRepository domain.PriceListRepository, redisCache *cache.RedisCache, validator domain.RequestValidator, stockObserver domain.StockObserver, mediaStorage domain.MediaStorage, tagRepository domain.TagRepository, attributeRepository domain.CategoryAttributeRepository, translationRepo domain.TranslationRepository) domain.ProductUsecase {
 	return &productUsecase{
-		productRepository: productRepository,
-		cache:             redisCache,
+		productRepository:   productRepository,
+		priceListRepository: priceListRepository,
+		cache:               redisCache,
+		validator:           validator,
+		stockObserver:       stockObserver,
+		mediaStorage:        mediaStorage,
+		tagRepository:       tagRepository,
+		attributeRepository: attributeRepository,
+		translationRepo:     translationRepo,
 	}
 }
 
 func (u *productUsecase) GetAllProducts(ctx context.Context) ([]domain.Product, error) {
-	return u.productRepository.GetAll(ctx)
+	products, err := u.productRepository.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.showProducts(ctx, pointers(products)...); err != nil {
+		return nil, err
+	}
+	return products, nil
 }
 
-func (u *productUsecase) GetAllProductsPaginated(ctx context.Context, params dto.ProductFilterParams, pq dto.PaginationQuery) (*dto.PaginatedResponse, error) {
+// GetAllProductsPaginated lists the products, priced in the price list named
+// by params.PriceList when it is set, with the facets asked for by fq. Only
+// the fields and relations in proj are loaded.
+func (u *productUsecase) GetAllProductsPaginated(ctx context.Context, params dto.ProductFilterParams, pq dto.PaginationQuery, fq dto.ProductFacetQuery, proj dto.Projection) (*dto.PaginatedResponse, *dto.ProductFacets, error) {
 	if pq.Page <= 0 {
 		pq.Page = 1
 	}
@@ -45,20 +85,137 @@ func (u *productUsecase) GetAllProductsPaginated(ctx context.Context, params dto
 		pq.Limit = 100
 	}
 
-	products, total, err := u.productRepository.GetAllPaginated(ctx, params, pq)
+	facets, err := parseFacets(fq.Facets)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	var priceList *domain.PriceList
+	var listCurrency string
+	if params.PriceList != "" {
+		if priceList, err = u.priceListRepository.GetByCode(ctx, params.PriceList); err != nil {
+			return nil, nil, err
+		}
+		listCurrency = priceList.Currency
+	}
+	if err := u.checkFilters(ctx, &params, listCurrency); err != nil {
+		return nil, nil, err
+	}
+
+	products, total, err := u.productRepository.GetAllPaginated(ctx, params, priceList, pq, proj)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := u.showProducts(ctx, pointers(products)...); err != nil {
+		return nil, nil, err
 	}
 
 	totalPages := int(math.Ceil(float64(total) / float64(pq.Limit)))
 
+	var result *dto.ProductFacets
+	if len(facets) > 0 {
+		if result, err = u.productFacets(ctx, params, priceList, facets); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	return &dto.PaginatedResponse{
 		Data:       products,
 		Page:       pq.Page,
 		Limit:      pq.Limit,
 		TotalItems: total,
 		TotalPages: totalPages,
-	}, nil
+	}, result, nil
+}
+
+// priceFacetBounds are the prices, in each currency, that the price facet
+// splits its buckets at.
+var priceFacetBounds = map[string][]string{
+	"IDR": {"100000", "500000", "1000000", "5000000", "10000000"},
+	"USD": {"10", "50", "100", "500", "1000"},
+}
+
+var facetNames = []string{"category", "price", "stock"}
+
+// parseFacets reads a comma-separated list of facet names.
+func parseFacets(s string) (map[string]bool, error) {
+	facets := map[string]bool{}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(facetNames, name) {
+			return nil, &domain.ValidationError{Fields: map[string]string{
+				"facets": "Must be a comma-separated list of " + strings.Join(facetNames, ", "),
+			}}
+		}
+		facets[name] = true
+	}
+	return facets, nil
+}
+
+// productFacets counts the requested facets. Each facet drops its own filter
+// from params, so choosing a category still shows the counts of the others.
+func (u *productUsecase) productFacets(ctx context.Context, params dto.ProductFilterParams, priceList *domain.PriceList, facets map[string]bool) (*dto.ProductFacets, error) {
+	var result dto.ProductFacets
+	var err error
+
+	if facets["category"] {
+		others := params.WithoutFilterOn("category_id")
+		others.CategoryID = nil
+		others.IncludeDescendants = false
+		if result.Category, err = u.productRepository.CountByCategory(ctx, others, priceList); err != nil {
+			return nil, err
+		}
+	}
+
+	if facets["price"] {
+		others := params
+		others.PriceMin = nil
+		others.PriceMax = nil
+		var listCurrency string
+		if priceList != nil {
+			listCurrency = priceList.Currency
+		}
+		currency := params.PriceCurrency(listCurrency)
+		bounds := make([]money.Money, 0, len(priceFacetBounds[currency]))
+		amounts := make([]int64, 0, len(priceFacetBounds[currency]))
+		for _, amount := range priceFacetBounds[currency] {
+			bound, err := money.Parse(amount, currency)
+			if err != nil {
+				return nil, err
+			}
+			bounds = append(bounds, bound)
+			amounts = append(amounts, bound.Amount)
+		}
+		counts, err := u.productRepository.CountByPriceBucket(ctx, others, priceList, currency, amounts)
+		if err != nil {
+			return nil, err
+		}
+		facet := dto.PriceFacet{Currency: currency, Buckets: make([]dto.PriceBucket, len(counts))}
+		for i, count := range counts {
+			facet.Buckets[i].Count = count
+			if i > 0 {
+				facet.Buckets[i].Min = &bounds[i-1]
+			}
+			if i < len(bounds) {
+				facet.Buckets[i].Max = &bounds[i]
+			}
+		}
+		result.Price = &facet
+	}
+
+	if facets["stock"] {
+		others := params.WithoutFilterOn("stock_quantity")
+		others.StockMin = nil
+		others.StockMax = nil
+		if result.Stock, err = u.productRepository.CountByStock(ctx, others, priceList); err != nil {
+			return nil, err
+		}
+	}
+
+	return &result, nil
 }
 
 func (u *productUsecase) GetProductReport(ctx context.Context) (*dto.ProductReportResponse, error) {
@@ -89,22 +246,162 @@ func (u *productUsecase) GetProductReport(ctx context.Context) (*dto.ProductRepo
 	return report, nil
 }
 
-func (u *productUsecase) GetProductByID(ctx context.Context, id int) (*domain.Product, error) {
+// SuggestProducts returns autocomplete suggestions for the query, which is
+// matched case-insensitively with its whitespace collapsed.
+func (u *productUsecase) SuggestProducts(ctx context.Context, query dto.ProductSuggestQuery) ([]dto.ProductSuggestion, error) {
+	q := strings.ToLower(strings.Join(strings.Fields(query.Q), " "))
+	limit := query.Limit
+	if limit <= 0 {
+		limit = suggestLimit
+	}
+	if q == "" {
+		return []dto.ProductSuggestion{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, suggestTimeout)
+	defer cancel()
+
+	cacheKey := fmt.Sprintf("%s%s:%d:%s", productCacheKey["suggest"], domain.LocaleFromContext(ctx), limit, q)
+	if u.cache != nil && u.cache.IsAvailable() {
+		cached, err := u.cache.Get(ctx, cacheKey)
+		if err == nil && cached != nil {
+			var suggestions []dto.ProductSuggestion
+			if json.Unmarshal(cached, &suggestions) == nil {
+				return suggestions, nil
+			}
+		}
+	}
+
+	suggestions, err := u.productRepository.Suggest(ctx, q, limit)
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, domain.ErrSuggestTimeout
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if u.cache != nil && u.cache.IsAvailable() {
+		data, err := json.Marshal(suggestions)
+		if err == nil {
+			if cacheErr := u.cache.Set(ctx, cacheKey, data, suggestCacheTTL); cacheErr != nil {
+				log.Printf("[CACHE] Failed to cache suggestions: %v", cacheErr)
+			}
+		}
+	}
+
+	return suggestions, nil
+}
+
+// CheckExportFilters checks the filters of an export before the download
+// starts. Exports are in the products' own prices, not a price list.
+func (u *productUsecase) CheckExportFilters(ctx context.Context, params *dto.ProductFilterParams) error {
+	return u.checkFilters(ctx, params, "")
+}
+
+// checkFilters looks up the types of the attributes the params filter on and
+// checks the params, with the price range in listCurrency when it is set.
+func (u *productUsecase) checkFilters(ctx context.Context, params *dto.ProductFilterParams, listCurrency string) error {
+	if len(params.Attr) > 0 {
+		types, err := u.attributeRepository.Types(ctx)
+		if err != nil {
+			return err
+		}
+		params.AttributeTypes = attributeFilterSchema(types)
+	}
+	fieldErrors := map[string]string{}
+	if invalid := params.InvalidPriceBounds(listCurrency); len(invalid) > 0 {
+		currency := params.PriceCurrency(listCurrency)
+		exp, _ := money.Exponent(currency)
+		for _, field := range invalid {
+			fieldErrors[field] = u.validator.Message(ctx, "price_bound", strconv.Itoa(exp), currency)
+		}
+	}
+	for param, err := range params.ExpressionErrors() {
+		fieldErrors[param] = u.validator.Message(ctx, err.Key, err.Args()...)
+	}
+	if len(fieldErrors) > 0 {
+		return &domain.ValidationError{Fields: fieldErrors}
+	}
+	return nil
+}
+
+// attributeFilterSchema maps attribute types onto the types of filter
+// values. Enums compare as strings.
+func attributeFilterSchema(types map[string]string) filter.Schema {
+	schema := make(filter.Schema, len(types))
+	for name, attributeType := range types {
+		switch attributeType {
+		case domain.AttributeNumber:
+			schema[name] = filter.Number
+		case domain.AttributeBoolean:
+			schema[name] = filter.Bool
+		default:
+			schema[name] = filter.String
+		}
+	}
+	return schema
+}
+
+// ExportProducts streams the products matching params, which must have been
+// through CheckExportFilters.
+func (u *productUsecase) ExportProducts(ctx context.Context, params dto.ProductFilterParams, fn func(row dto.ProductExportRow) error) error {
+	return u.productRepository.StreamForExport(ctx, params, fn)
+}
+
+// GetProductByID returns the fields and relations of the product in proj,
+// with its price in the price list named by priceList unless that is empty.
+func (u *productUsecase) GetProductByID(ctx context.Context, id int, priceList string, proj dto.Projection) (*domain.Product, error) {
 	if id <= 0 {
 		return nil, errors.New("invalid ID")
 	}
-	return u.productRepository.GetByID(ctx, id)
+	var list *domain.PriceList
+	if priceList != "" {
+		var err error
+		if list, err = u.priceListRepository.GetByCode(ctx, priceList); err != nil {
+			return nil, err
+		}
+	}
+	product, err := u.productRepository.GetByIDProjected(ctx, id, list, proj)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.showProducts(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// GetProductBySlug resolves current and retired slugs alike; the returned
+// product always carries its current slug.
+func (u *productUsecase) GetProductBySlug(ctx context.Context, slug string, proj dto.Projection) (*domain.Product, error) {
+	if slug == "" {
+		return nil, errors.New("invalid slug")
+	}
+	product, err := u.productRepository.GetBySlug(ctx, slug, proj)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.showProducts(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
 }
 
+// CreateProduct resolves the product's tags, which only need their slugs
+// set, and checks its attributes before creating it.
 func (u *productUsecase) CreateProduct(ctx context.Context, product *domain.Product) error {
-	err := u.productRepository.Create(ctx, product)
+	if err := u.prepareProduct(ctx, product); err != nil {
+		return err
+	}
+	err := u.writeError(ctx, u.productRepository.Create(ctx, product))
 	if err == nil {
 		u.invalidateReportCache(ctx, productCacheKey["report"])
+		u.stockObserver.StockChanged(product.ID)
 	}
 	return err
 }
 
-func (u *productUsecase) EditProduct(ctx context.Context, id int, req *dto.UpdateProductRequest) (*domain.Product, error) {
+func (u *productUsecase) ReplaceProduct(ctx context.Context, id int, req *dto.ReplaceProductRequest) (*domain.Product, error) {
 	if id <= 0 {
 		return nil, errors.New("invalid ID")
 	}
@@ -113,44 +410,220 @@ func (u *productUsecase) EditProduct(ctx context.Context, id int, req *dto.Updat
 	if err != nil {
 		return nil, err
 	}
+	return u.replaceProduct(ctx, product, req)
+}
 
-	if req.Name != nil {
-		product.Name = *req.Name
+// PatchProduct applies the patch to the product's ReplaceProductRequest
+// representation and validates the result before saving it.
+func (u *productUsecase) PatchProduct(ctx context.Context, id int, patch domain.Patch) (*domain.Product, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
 	}
-	if req.Description != nil {
-		product.Description = *req.Description
+
+	product, err := u.productRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
 	}
-	if req.Price != nil {
-		product.Price = *req.Price
+
+	current := dto.ReplaceProductRequest{
+		Name:             product.Name,
+		Description:      product.Description,
+		Price:            dto.NewMoneyRequest(product.Price),
+		IsActive:         product.IsActive,
+		CategoryID:       product.CategoryID,
+		ReorderThreshold: product.ReorderThreshold,
+		Tags:             tagSlugs(product.Tags),
+		Attributes:       product.Attributes,
 	}
-	if req.StockQuantity != nil {
-		product.StockQuantity = *req.StockQuantity
+	var req dto.ReplaceProductRequest
+	if err := applyPatch(patch, current, &req); err != nil {
+		return nil, err
 	}
-	if req.IsActive != nil {
-		product.IsActive = *req.IsActive
+	fieldErrors, err := u.validator.ValidateStruct(ctx, &req)
+	if err != nil {
+		return nil, err
 	}
-	if req.CategoryID != nil {
-		product.CategoryID = *req.CategoryID
+	if len(fieldErrors) > 0 {
+		return nil, &domain.ValidationError{Fields: fieldErrors}
 	}
+	return u.replaceProduct(ctx, product, &req)
+}
 
-	if err := u.productRepository.Edit(ctx, product); err != nil {
+func (u *productUsecase) replaceProduct(ctx context.Context, product *domain.Product, req *dto.ReplaceProductRequest) (*domain.Product, error) {
+	if req.CategoryID != product.CategoryID {
+		product.Category = domain.Category{}
+	}
+	product.Name = req.Name
+	product.Description = req.Description
+	product.Price = req.Price.Money()
+	product.IsActive = req.IsActive
+	product.CategoryID = req.CategoryID
+	product.ReorderThreshold = req.ReorderThreshold
+	// A replacement without tags or attributes has none.
+	product.Tags = domain.TagRefs(req.Tags)
+	if product.Tags == nil {
+		product.Tags = []domain.Tag{}
+	}
+	product.Attributes = req.Attributes
+	if err := u.prepareProduct(ctx, product); err != nil {
+		return nil, err
+	}
+
+	if err := u.writeError(ctx, u.productRepository.Edit(ctx, product)); err != nil {
 		return nil, err
 	}
 	u.invalidateReportCache(ctx, productCacheKey["report"])
+	u.stockObserver.StockChanged(product.ID)
+	u.setMediaURLs(product)
 	return product, nil
 }
 
+// DeleteProduct deletes the product together with its media files.
 func (u *productUsecase) DeleteProduct(ctx context.Context, id int) error {
 	if id <= 0 {
 		return errors.New("invalid ID")
 	}
-	err := u.productRepository.Delete(ctx, id)
-	if err == nil {
-		u.invalidateReportCache(ctx, productCacheKey["report"])
+	media, err := u.productRepository.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.invalidateReportCache(ctx, productCacheKey["report"])
+	u.deleteMediaFiles(ctx, media)
+	return nil
+}
+
+// prepareProduct checks the product against the rules on stored data,
+// resolves the slugs of its tags, unless Tags is nil, and checks its
+// attributes against the schema of its category. A null attribute value
+// removes the attribute.
+func (u *productUsecase) prepareProduct(ctx context.Context, product *domain.Product) error {
+	fieldErrors, err := u.validator.ValidateStruct(ctx, product)
+	if err != nil {
+		return err
+	}
+	if fieldErrors == nil {
+		fieldErrors = map[string]string{}
+	}
+	if product.Tags != nil {
+		slugs := tagSlugs(product.Tags)
+		tags, err := u.tagRepository.GetBySlugs(ctx, slugs)
+		if err != nil {
+			return err
+		}
+		var unknown []string
+		for _, s := range slugs {
+			if !slices.ContainsFunc(tags, func(tag domain.Tag) bool { return tag.Slug == s }) {
+				unknown = append(unknown, s)
+			}
+		}
+		if len(unknown) > 0 {
+			fieldErrors["tags"] = u.validator.Message(ctx, "tags_unknown", strings.Join(unknown, ", "))
+		}
+		product.Tags = tags
+	}
+
+	if product.Attributes == nil {
+		product.Attributes = domain.ProductAttributes{}
+	}
+	maps.DeleteFunc(product.Attributes, func(_ string, value interface{}) bool { return value == nil })
+	schema, err := u.attributeRepository.GetEffective(ctx, product.CategoryID)
+	switch {
+	case errors.Is(err, domain.ErrCategoryNotFound):
+		// Normally reported by the category_exists rule already.
+		if _, ok := fieldErrors["category_id"]; !ok {
+			fieldErrors["category_id"] = u.validator.Message(ctx, "category_exists")
+		}
+	case err != nil:
+		return err
+	default:
+		maps.Copy(fieldErrors, attributeErrors(ctx, u.validator, schema, product.Attributes))
+	}
+
+	if len(fieldErrors) > 0 {
+		return &domain.ValidationError{Fields: fieldErrors}
+	}
+	return nil
+}
+
+// writeError reports a name another product of the category took since
+// prepareProduct checked it like the unique_name rule does.
+func (u *productUsecase) writeError(ctx context.Context, err error) error {
+	if errors.Is(err, domain.ErrProductNameTaken) {
+		return &domain.ValidationError{Fields: map[string]string{"name": u.validator.Message(ctx, "unique_name")}}
 	}
 	return err
 }
 
+// attributeErrors checks attribute values against the attributes that apply
+// to the product, keyed like "attributes.screen_size", with messages in the
+// locale of ctx.
+func attributeErrors(ctx context.Context, v domain.RequestValidator, schema []domain.CategoryAttribute, values domain.ProductAttributes) map[string]string {
+	fieldErrors := map[string]string{}
+	for name, problem := range domain.CheckAttributes(schema, values) {
+		fieldErrors["attributes."+name] = v.Message(ctx, problem.Key, problem.Params...)
+	}
+	return fieldErrors
+}
+
+func tagSlugs(tags []domain.Tag) []string {
+	if tags == nil {
+		return nil
+	}
+	slugs := make([]string, len(tags))
+	for i, tag := range tags {
+		slugs[i] = tag.Slug
+	}
+	return slugs
+}
+
+func (u *productUsecase) setMediaURLs(product *domain.Product) {
+	setMediaURLs(u.mediaStorage, product.Media)
+}
+
+// showProducts prepares products read for a client: their media get URLs
+// and their text is shown in the locale of ctx.
+func (u *productUsecase) showProducts(ctx context.Context, products ...*domain.Product) error {
+	for _, product := range products {
+		u.setMediaURLs(product)
+	}
+	return localizeProducts(ctx, u.translationRepo, products...)
+}
+
+func (u *productUsecase) deleteMediaFiles(ctx context.Context, media []domain.ProductMedia) {
+	deleteMediaFiles(ctx, u.mediaStorage, media)
+}
+
+// applyProductUpdate copies the fields set in req onto product.
+func applyProductUpdate(product *domain.Product, req *dto.UpdateProductRequest) {
+	if req.Name != nil {
+		product.Name = *req.Name
+	}
+	if req.Description != nil {
+		product.Description = *req.Description
+	}
+	if req.Price != nil {
+		product.Price = req.Price.Money()
+	}
+	if req.IsActive != nil {
+		product.IsActive = *req.IsActive
+	}
+	if req.ReorderThreshold != nil {
+		product.ReorderThreshold = req.ReorderThreshold
+	}
+	if req.Tags != nil {
+		product.Tags = domain.TagRefs(req.Tags)
+	}
+	if req.Attributes != nil {
+		product.Attributes = req.Attributes
+	}
+	if req.CategoryID != nil && *req.CategoryID != product.CategoryID {
+		product.CategoryID = *req.CategoryID
+		// A preloaded Category would make Save write its ID back into
+		// CategoryID, undoing the change.
+		product.Category = domain.Category{}
+	}
+}
+
 func (u *productUsecase) invalidateReportCache(ctx context.Context, cacheKey string) {
 	if u.cache != nil && u.cache.IsAvailable() {
 		if err := u.cache.Delete(ctx, cacheKey); err != nil {
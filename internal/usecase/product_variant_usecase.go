@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"maps"
+	"strings"
+	"test-elabram/internal/cache"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+)
+
+type productVariantUsecase struct {
+	variantRepository domain.ProductVariantRepository
+	cache             *cache.RedisCache
+	stockObserver     domain.StockObserver
+}
+
+func NewProductVariantUsecase(variantRepository domain.ProductVariantRepository, redisCache *cache.RedisCache, stockObserver domain.StockObserver) domain.ProductVariantUsecase {
+	return &productVariantUsecase{
+		variantRepository: variantRepository,
+		cache:             redisCache,
+		stockObserver:     stockObserver,
+	}
+}
+
+func (u *productVariantUsecase) GetVariants(ctx context.Context, productID int) ([]domain.ProductVariant, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.variantRepository.GetByProductID(ctx, productID)
+}
+
+func (u *productVariantUsecase) GetVariant(ctx context.Context, productID int, id int) (*domain.ProductVariant, error) {
+	if productID <= 0 || id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.variantRepository.GetByID(ctx, productID, id)
+}
+
+func (u *productVariantUsecase) CreateVariant(ctx context.Context, productID int, req *dto.VariantRequest) (*domain.ProductVariant, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	if err := u.checkOptionsUnique(ctx, productID, 0, req.Options); err != nil {
+		return nil, err
+	}
+
+	variant := domain.ProductVariant{ProductID: uint(productID)}
+	applyVariantRequest(&variant, req)
+	if err := u.variantRepository.Create(ctx, &variant); err != nil {
+		return nil, err
+	}
+	u.invalidateReportCache(ctx)
+	u.stockObserver.StockChanged(uint(productID))
+	return &variant, nil
+}
+
+// ReplaceVariant overwrites every field of the variant with the request.
+func (u *productVariantUsecase) ReplaceVariant(ctx context.Context, productID int, id int, req *dto.ReplaceVariantRequest) (*domain.ProductVariant, error) {
+	if productID <= 0 || id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	variant, err := u.variantRepository.GetByID(ctx, productID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkOptionsUnique(ctx, productID, variant.ID, req.Options); err != nil {
+		return nil, err
+	}
+
+	variant.SKU = strings.TrimSpace(req.SKU)
+	variant.Options = variantOptions(req.Options)
+	variant.Price = req.Price.Money()
+	variant.IsActive = req.IsActive
+	if err := u.variantRepository.Edit(ctx, variant); err != nil {
+		return nil, err
+	}
+	u.invalidateReportCache(ctx)
+	u.stockObserver.StockChanged(uint(productID))
+	return variant, nil
+}
+
+func (u *productVariantUsecase) DeleteVariant(ctx context.Context, productID int, id int) error {
+	if productID <= 0 || id <= 0 {
+		return errors.New("invalid ID")
+	}
+	if err := u.variantRepository.Delete(ctx, productID, id); err != nil {
+		return err
+	}
+	u.invalidateReportCache(ctx)
+	u.stockObserver.StockChanged(uint(productID))
+	return nil
+}
+
+// checkOptionsUnique rejects options that another variant of the product
+// already has, since the two could not be told apart. selfID is the variant
+// being replaced, or 0.
+func (u *productVariantUsecase) checkOptionsUnique(ctx context.Context, productID int, selfID uint, options map[string]string) error {
+	variants, err := u.variantRepository.GetByProductID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	for _, v := range variants {
+		if v.ID != selfID && maps.Equal(v.Options, domain.VariantOptions(options)) {
+			return domain.ErrDuplicateVariantOptions
+		}
+	}
+	return nil
+}
+
+func applyVariantRequest(variant *domain.ProductVariant, req *dto.VariantRequest) {
+	variant.SKU = strings.TrimSpace(req.SKU)
+	variant.Options = variantOptions(req.Options)
+	variant.Price = req.Price.Money()
+	variant.StockQuantity = req.StockQuantity
+	variant.IsActive = req.IsActive
+}
+
+// variantOptions returns the options of a request, which are never null.
+func variantOptions(options map[string]string) domain.VariantOptions {
+	if options == nil {
+		return domain.VariantOptions{}
+	}
+	return domain.VariantOptions(options)
+}
+
+func (u *productVariantUsecase) invalidateReportCache(ctx context.Context) {
+	if u.cache != nil && u.cache.IsAvailable() {
+		if err := u.cache.Delete(ctx, productCacheKey["report"]); err != nil {
+			log.Printf("[CACHE] Failed to invalidate report cache: %v", err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"test-elabram/internal/cache"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"time"
+)
+
+const defaultReservationTTL = 10 * time.Minute
+
+type reservationUsecase struct {
+	reservationRepository domain.ReservationRepository
+	cache                 *cache.RedisCache
+	stockObserver         domain.StockObserver
+}
+
+func NewReservationUsecase(reservationRepository domain.ReservationRepository, redisCache *cache.RedisCache, stockObserver domain.StockObserver) domain.ReservationUsecase {
+	return &reservationUsecase{
+		reservationRepository: reservationRepository,
+		cache:                 redisCache,
+		stockObserver:         stockObserver,
+	}
+}
+
+func (u *reservationUsecase) GetReservation(ctx context.Context, id int) (*domain.Reservation, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.reservationRepository.GetByID(ctx, id)
+}
+
+// CreateReservation holds the requested quantities until the reservation
+// expires. It fails with domain.ErrInsufficientStock when an item exceeds
+// the available quantity.
+func (u *reservationUsecase) CreateReservation(ctx context.Context, req *dto.ReservationRequest) (*domain.Reservation, error) {
+	ttl := defaultReservationTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	reservation := domain.Reservation{
+		Status:    dto.ReservationActive,
+		Reference: strings.TrimSpace(req.Reference),
+		Actor:     domain.ActorFromContext(ctx),
+		ExpiresAt: time.Now().Add(ttl),
+		Items:     make([]domain.ReservationItem, 0, len(req.Items)),
+	}
+	for _, item := range req.Items {
+		reservation.Items = append(reservation.Items, domain.ReservationItem{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Quantity:  item.Quantity,
+		})
+	}
+	if err := u.reservationRepository.Create(ctx, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ConfirmReservation turns the held items into sales through the stock
+// ledger.
+func (u *reservationUsecase) ConfirmReservation(ctx context.Context, id int, req *dto.ConfirmReservationRequest) (*domain.Reservation, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	reservation, err := u.reservationRepository.Confirm(ctx, id, req.WarehouseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.cache != nil && u.cache.IsAvailable() {
+		if err := u.cache.Delete(ctx, productCacheKey["report"]); err != nil {
+			log.Printf("[CACHE] Failed to invalidate report cache: %v", err)
+		}
+	}
+
+	productIDs := make([]uint, 0, len(reservation.Items))
+	for _, item := range reservation.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	u.stockObserver.StockChanged(productIDs...)
+	return reservation, nil
+}
+
+func (u *reservationUsecase) ReleaseReservation(ctx context.Context, id int) (*domain.Reservation, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.reservationRepository.Release(ctx, id)
+}
+
+// ExpireReservations marks the holds that have run out as expired. Expired
+// holds already stop counting against the available quantity; sweeping
+// keeps their status truthful.
+func (u *reservationUsecase) ExpireReservations(ctx context.Context) (int64, error) {
+	return u.reservationRepository.ExpireHolds(ctx, time.Now())
+}
+
+// RunSweeper expires holds every interval until ctx is done.
+func (u *reservationUsecase) RunSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			expired, err := u.ExpireReservations(ctx)
+			if err != nil {
+				log.Printf("[RESERVATION] Failed to expire holds: %v", err)
+				continue
+			}
+			if expired > 0 {
+				log.Printf("[RESERVATION] Expired %d holds", expired)
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"maps"
+	"math"
+	"slices"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"time"
+)
+
+// stockAlertQueueSize bounds the pending evaluation requests. When it is
+// full, requests are dropped and picked up by the next periodic pass.
+const stockAlertQueueSize = 256
+
+type stockAlertUsecase struct {
+	alertRepository domain.StockAlertRepository
+	notifiers       []domain.StockAlertNotifier
+	queue           chan []uint
+}
+
+func NewStockAlertUsecase(alertRepository domain.StockAlertRepository, notifiers []domain.StockAlertNotifier) domain.StockAlertUsecase {
+	return &stockAlertUsecase{
+		alertRepository: alertRepository,
+		notifiers:       notifiers,
+		queue:           make(chan []uint, stockAlertQueueSize),
+	}
+}
+
+// StockChanged queues the products for evaluation by Run.
+func (u *stockAlertUsecase) StockChanged(productIDs ...uint) {
+	if len(productIDs) == 0 {
+		return
+	}
+	select {
+	case u.queue <- productIDs:
+	default:
+		log.Printf("[ALERT] Evaluation queue is full, deferring %d products to the next pass", len(productIDs))
+	}
+}
+
+func (u *stockAlertUsecase) GetLowStockProducts(ctx context.Context, pq dto.PaginationQuery) (*dto.PaginatedResponse, error) {
+	if pq.Page <= 0 {
+		pq.Page = 1
+	}
+	if pq.Limit <= 0 {
+		pq.Limit = 10
+	}
+	if pq.Limit > 100 {
+		pq.Limit = 100
+	}
+
+	statuses, total, err := u.alertRepository.GetLowStock(ctx, pq)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PaginatedResponse{
+		Data:       statuses,
+		Page:       pq.Page,
+		Limit:      pq.Limit,
+		TotalItems: total,
+		TotalPages: int(math.Ceil(float64(total) / float64(pq.Limit))),
+	}, nil
+}
+
+// Evaluate checks the products, or with no IDs every product with a
+// threshold or an open alert. A product that crossed its threshold gets an
+// alert sent through every notifier; one that recovered has its alert
+// closed, so the next crossing alerts again. Opening the alert claims the
+// crossing; when a notifier fails the alert is discarded, so the next
+// evaluation sends it again, to every notifier.
+func (u *stockAlertUsecase) Evaluate(ctx context.Context, productIDs []uint) error {
+	statuses, err := u.alertRepository.GetStockStatus(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, status := range statuses {
+		if status.ReorderThreshold == nil || status.StockQuantity > *status.ReorderThreshold {
+			if _, err := u.alertRepository.ResolveAlert(ctx, status.ProductID); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		opened, err := u.alertRepository.OpenAlert(ctx, status)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !opened {
+			continue
+		}
+		var failed bool
+		for _, notifier := range u.notifiers {
+			if err := notifier.Notify(ctx, status); err != nil {
+				log.Printf("[ALERT] %s notifier failed for product %d: %v", notifier.Name(), status.ProductID, err)
+				failed = true
+			}
+		}
+		if failed {
+			if err := u.alertRepository.DiscardAlert(ctx, status.ProductID); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run evaluates queued products as they come in, and every product with a
+// threshold every interval, which also catches threshold changes on
+// categories and stock changed by imports. It returns when ctx is done.
+func (u *stockAlertUsecase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case productIDs := <-u.queue:
+			if err := u.Evaluate(ctx, u.drainQueue(productIDs)); err != nil {
+				log.Printf("[ALERT] Failed to evaluate stock: %v", err)
+			}
+		case <-ticker.C:
+			if err := u.Evaluate(ctx, nil); err != nil {
+				log.Printf("[ALERT] Failed to evaluate stock: %v", err)
+			}
+		}
+	}
+}
+
+// drainQueue merges the requests waiting in the queue into productIDs, so a
+// burst of changes is evaluated in one query.
+func (u *stockAlertUsecase) drainQueue(productIDs []uint) []uint {
+	pending := map[uint]bool{}
+	for _, id := range productIDs {
+		pending[id] = true
+	}
+	for {
+		select {
+		case more := <-u.queue:
+			for _, id := range more {
+				pending[id] = true
+			}
+		default:
+			return slices.Sorted(maps.Keys(pending))
+		}
+	}
+}
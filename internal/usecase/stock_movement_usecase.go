@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"test-elabram/internal/cache"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+)
+
+type stockMovementUsecase struct {
+	movementRepository domain.StockMovementRepository
+	cache              *cache.RedisCache
+	stockObserver      domain.StockObserver
+	validator          domain.RequestValidator
+}
+
+func NewStockMovementUsecase(movementRepository domain.StockMovementRepository, redisCache *cache.RedisCache, stockObserver domain.StockObserver, validator domain.RequestValidator) domain.StockMovementUsecase {
+	return &stockMovementUsecase{
+		movementRepository: movementRepository,
+		cache:              redisCache,
+		stockObserver:      stockObserver,
+		validator:          validator,
+	}
+}
+
+// RecordMovement changes the stock of a product or variant in one warehouse
+// through the ledger. Receipts and returns add the quantity and sales remove
+// it; adjustments apply it as a signed change.
+func (u *stockMovementUsecase) RecordMovement(ctx context.Context, productID int, req *dto.StockMovementRequest) (*domain.StockMovement, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+
+	quantity := req.Quantity
+	switch req.Type {
+	case dto.MovementReceipt, dto.MovementReturn, dto.MovementSale:
+		if quantity <= 0 {
+			return nil, &domain.ValidationError{Fields: map[string]string{"quantity": u.validator.Message(ctx, "gt-number", "0")}}
+		}
+		if req.Type == dto.MovementSale {
+			quantity = -quantity
+		}
+	}
+
+	movement := domain.StockMovement{
+		ProductID:   uint(productID),
+		VariantID:   req.VariantID,
+		WarehouseID: req.WarehouseID,
+		Type:        req.Type,
+		Quantity:    quantity,
+		Reason:      req.Reason,
+		Actor:       domain.ActorFromContext(ctx),
+	}
+	if err := u.movementRepository.Record(ctx, &movement); err != nil {
+		return nil, err
+	}
+	u.invalidateReportCache(ctx)
+	u.stockObserver.StockChanged(movement.ProductID)
+	return &movement, nil
+}
+
+// TransferStock moves stock between two warehouses. It returns the outgoing
+// and the incoming movement.
+func (u *stockMovementUsecase) TransferStock(ctx context.Context, req *dto.StockTransferRequest) ([]domain.StockMovement, error) {
+	if req.FromWarehouseID == req.ToWarehouseID {
+		return nil, domain.ErrSameWarehouseTarget
+	}
+
+	actor := domain.ActorFromContext(ctx)
+	out := domain.StockMovement{
+		ProductID:   req.ProductID,
+		VariantID:   req.VariantID,
+		WarehouseID: &req.FromWarehouseID,
+		Type:        dto.MovementTransfer,
+		Quantity:    -req.Quantity,
+		Reason:      req.Reason,
+		Actor:       actor,
+	}
+	in := out
+	in.WarehouseID = &req.ToWarehouseID
+	in.Quantity = req.Quantity
+	if err := u.movementRepository.Transfer(ctx, &out, &in); err != nil {
+		return nil, err
+	}
+	u.invalidateReportCache(ctx)
+	return []domain.StockMovement{out, in}, nil
+}
+
+func (u *stockMovementUsecase) GetMovements(ctx context.Context, productID int, query dto.StockMovementQuery, pq dto.PaginationQuery) (*dto.PaginatedResponse, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	if pq.Page <= 0 {
+		pq.Page = 1
+	}
+	if pq.Limit <= 0 {
+		pq.Limit = 10
+	}
+	if pq.Limit > 100 {
+		pq.Limit = 100
+	}
+
+	movements, total, err := u.movementRepository.GetByProductID(ctx, productID, query, pq)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PaginatedResponse{
+		Data:       movements,
+		Page:       pq.Page,
+		Limit:      pq.Limit,
+		TotalItems: total,
+		TotalPages: int(math.Ceil(float64(total) / float64(pq.Limit))),
+	}, nil
+}
+
+// invalidateReportCache drops the cached product report, which sums up the
+// stock.
+func (u *stockMovementUsecase) invalidateReportCache(ctx context.Context) {
+	if u.cache != nil && u.cache.IsAvailable() {
+		if err := u.cache.Delete(ctx, productCacheKey["report"]); err != nil {
+			log.Printf("[CACHE] Failed to invalidate report cache: %v", err)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/slug"
+)
+
+type tagUsecase struct {
+	tagRepository domain.TagRepository
+	validator     domain.RequestValidator
+}
+
+func NewTagUsecase(tagRepository domain.TagRepository, validator domain.RequestValidator) domain.TagUsecase {
+	return &tagUsecase{
+		tagRepository: tagRepository,
+		validator:     validator,
+	}
+}
+
+func (u *tagUsecase) GetAllTags(ctx context.Context) ([]domain.Tag, error) {
+	return u.tagRepository.GetAll(ctx)
+}
+
+func (u *tagUsecase) CreateTag(ctx context.Context, req *dto.TagRequest) (*domain.Tag, error) {
+	tag := domain.Tag{}
+	if err := u.applyTagRequest(ctx, &tag, req); err != nil {
+		return nil, err
+	}
+	if err := u.tagRepository.Create(ctx, &tag); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// ReplaceTag renames the tag. Its slug follows the name, so filters and
+// requests must use the new slug afterwards.
+func (u *tagUsecase) ReplaceTag(ctx context.Context, id int, req *dto.TagRequest) (*domain.Tag, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	tag := domain.Tag{ID: uint(id)}
+	if err := u.applyTagRequest(ctx, &tag, req); err != nil {
+		return nil, err
+	}
+	if err := u.tagRepository.Edit(ctx, &tag); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (u *tagUsecase) DeleteTag(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid ID")
+	}
+	return u.tagRepository.Delete(ctx, id)
+}
+
+// applyTagRequest sets the name of the tag and the slug derived from it.
+func (u *tagUsecase) applyTagRequest(ctx context.Context, tag *domain.Tag, req *dto.TagRequest) error {
+	tag.Name = req.Name
+	tag.Slug = slug.Make(req.Name)
+	if tag.Slug == "" {
+		return &domain.ValidationError{Fields: map[string]string{"name": u.validator.Message(ctx, "slug")}}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+	"test-elabram/internal/locale"
+)
+
+type translationUsecase struct {
+	translationRepo domain.TranslationRepository
+}
+
+func NewTranslationUsecase(translationRepo domain.TranslationRepository) domain.TranslationUsecase {
+	return &translationUsecase{
+		translationRepo: translationRepo,
+	}
+}
+
+func (u *translationUsecase) GetProductTranslations(ctx context.Context, productID int) ([]domain.ProductTranslation, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.translationRepo.GetProductTranslations(ctx, productID)
+}
+
+// SaveProductTranslation creates or replaces the product's translation into
+// l, which must be one of locale.Translations.
+func (u *translationUsecase) SaveProductTranslation(ctx context.Context, productID int, l string, req *dto.TranslationRequest) (*domain.ProductTranslation, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	if err := checkTranslationLocale(l); err != nil {
+		return nil, err
+	}
+	translation := domain.ProductTranslation{
+		ProductID:   uint(productID),
+		Locale:      l,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := u.translationRepo.SaveProductTranslation(ctx, &translation); err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
+
+func (u *translationUsecase) DeleteProductTranslation(ctx context.Context, productID int, l string) error {
+	if productID <= 0 {
+		return errors.New("invalid ID")
+	}
+	if err := checkTranslationLocale(l); err != nil {
+		return err
+	}
+	return u.translationRepo.DeleteProductTranslation(ctx, productID, l)
+}
+
+func (u *translationUsecase) GetCategoryTranslations(ctx context.Context, categoryID int) ([]domain.CategoryTranslation, error) {
+	if categoryID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.translationRepo.GetCategoryTranslations(ctx, categoryID)
+}
+
+// SaveCategoryTranslation creates or replaces the category's translation
+// into l, which must be one of locale.Translations.
+func (u *translationUsecase) SaveCategoryTranslation(ctx context.Context, categoryID int, l string, req *dto.TranslationRequest) (*domain.CategoryTranslation, error) {
+	if categoryID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	if err := checkTranslationLocale(l); err != nil {
+		return nil, err
+	}
+	translation := domain.CategoryTranslation{
+		CategoryID:  uint(categoryID),
+		Locale:      l,
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := u.translationRepo.SaveCategoryTranslation(ctx, &translation); err != nil {
+		return nil, err
+	}
+	return &translation, nil
+}
+
+func (u *translationUsecase) DeleteCategoryTranslation(ctx context.Context, categoryID int, l string) error {
+	if categoryID <= 0 {
+		return errors.New("invalid ID")
+	}
+	if err := checkTranslationLocale(l); err != nil {
+		return err
+	}
+	return u.translationRepo.DeleteCategoryTranslation(ctx, categoryID, l)
+}
+
+func (u *translationUsecase) GetMissingProductTranslations(ctx context.Context, query dto.MissingTranslationQuery, pq dto.PaginationQuery) (*dto.PaginatedResponse, error) {
+	return missingTranslations(ctx, u.translationRepo.GetMissingProducts, query, pq)
+}
+
+func (u *translationUsecase) GetMissingCategoryTranslations(ctx context.Context, query dto.MissingTranslationQuery, pq dto.PaginationQuery) (*dto.PaginatedResponse, error) {
+	return missingTranslations(ctx, u.translationRepo.GetMissingCategories, query, pq)
+}
+
+// missingTranslations pages through what list reports missing, in the
+// locale of the query or else in every translated one.
+func missingTranslations(ctx context.Context, list func(context.Context, []string, dto.PaginationQuery) ([]dto.MissingTranslation, int64, error), query dto.MissingTranslationQuery, pq dto.PaginationQuery) (*dto.PaginatedResponse, error) {
+	if pq.Page <= 0 {
+		pq.Page = 1
+	}
+	if pq.Limit <= 0 {
+		pq.Limit = 10
+	}
+	if pq.Limit > 100 {
+		pq.Limit = 100
+	}
+
+	locales := locale.Translations()
+	if query.Locale != "" {
+		if err := checkTranslationLocale(query.Locale); err != nil {
+			return nil, err
+		}
+		locales = []string{query.Locale}
+	}
+
+	items, total, err := list(ctx, locales, pq)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PaginatedResponse{
+		Data:       items,
+		Page:       pq.Page,
+		Limit:      pq.Limit,
+		TotalItems: total,
+		TotalPages: int(math.Ceil(float64(total) / float64(pq.Limit))),
+	}, nil
+}
+
+// checkTranslationLocale returns domain.ErrUnsupportedLocale unless l is a
+// locale content is translated into. locale.Default is not one: its text is
+// the product's or category's own.
+func checkTranslationLocale(l string) error {
+	if !slices.Contains(locale.Translations(), l) {
+		return domain.ErrUnsupportedLocale
+	}
+	return nil
+}
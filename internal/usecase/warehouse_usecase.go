@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"test-elabram/internal/cache"
+	"test-elabram/internal/domain"
+	"test-elabram/internal/dto"
+)
+
+type warehouseUsecase struct {
+	warehouseRepository domain.WarehouseRepository
+	cache               *cache.RedisCache
+}
+
+func NewWarehouseUsecase(warehouseRepository domain.WarehouseRepository, redisCache *cache.RedisCache) domain.WarehouseUsecase {
+	return &warehouseUsecase{
+		warehouseRepository: warehouseRepository,
+		cache:               redisCache,
+	}
+}
+
+func (u *warehouseUsecase) GetAllWarehouses(ctx context.Context) ([]domain.Warehouse, error) {
+	return u.warehouseRepository.GetAll(ctx)
+}
+
+func (u *warehouseUsecase) GetWarehouseByID(ctx context.Context, id int) (*domain.Warehouse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.warehouseRepository.GetByID(ctx, id)
+}
+
+func (u *warehouseUsecase) CreateWarehouse(ctx context.Context, req *dto.WarehouseRequest) (*domain.Warehouse, error) {
+	warehouse := domain.Warehouse{}
+	applyWarehouseRequest(&warehouse, req)
+	if err := u.warehouseRepository.Create(ctx, &warehouse); err != nil {
+		return nil, err
+	}
+	u.invalidateReportCache(ctx)
+	return &warehouse, nil
+}
+
+// ReplaceWarehouse overwrites every field of the warehouse with the request.
+// The default warehouse stays the default until another one takes over.
+func (u *warehouseUsecase) ReplaceWarehouse(ctx context.Context, id int, req *dto.WarehouseRequest) (*domain.Warehouse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	warehouse := domain.Warehouse{ID: uint(id)}
+	applyWarehouseRequest(&warehouse, req)
+	if err := u.warehouseRepository.Edit(ctx, &warehouse); err != nil {
+		return nil, err
+	}
+	u.invalidateReportCache(ctx)
+	return &warehouse, nil
+}
+
+func (u *warehouseUsecase) DeleteWarehouse(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid ID")
+	}
+	if err := u.warehouseRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	u.invalidateReportCache(ctx)
+	return nil
+}
+
+func (u *warehouseUsecase) GetWarehouseStock(ctx context.Context, id int) ([]domain.StockLevel, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.warehouseRepository.GetStockLevels(ctx, id)
+}
+
+func (u *warehouseUsecase) GetProductStockLevels(ctx context.Context, productID int) ([]domain.StockLevel, error) {
+	if productID <= 0 {
+		return nil, errors.New("invalid ID")
+	}
+	return u.warehouseRepository.GetProductStockLevels(ctx, productID)
+}
+
+func applyWarehouseRequest(warehouse *domain.Warehouse, req *dto.WarehouseRequest) {
+	warehouse.Code = strings.ToUpper(strings.TrimSpace(req.Code))
+	warehouse.Name = req.Name
+	warehouse.IsDefault = req.IsDefault
+}
+
+// invalidateReportCache drops the cached product report, which breaks the
+// stock down by warehouse.
+func (u *warehouseUsecase) invalidateReportCache(ctx context.Context) {
+	if u.cache != nil && u.cache.IsAvailable() {
+		if err := u.cache.Delete(ctx, productCacheKey["report"]); err != nil {
+			log.Printf("[CACHE] Failed to invalidate report cache: %v", err)
+		}
+	}
+}
@@ -0,0 +1,8 @@
+-- Modify "categories" table
+ALTER TABLE "public"."categories" ADD COLUMN "parent_id" bigint NULL, ADD COLUMN "path" text NOT NULL DEFAULT '';
+-- Backfill the materialized path of the existing (top-level) categories
+UPDATE "public"."categories" SET "path" = '/' || "id" || '/';
+-- Create index "idx_categories_parent_id" to table: "categories"
+CREATE INDEX "idx_categories_parent_id" ON "public"."categories" ("parent_id");
+-- Create index "idx_categories_path" to table: "categories"
+CREATE INDEX "idx_categories_path" ON "public"."categories" ("path");
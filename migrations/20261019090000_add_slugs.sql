@@ -0,0 +1,41 @@
+-- Modify "categories" table
+ALTER TABLE "public"."categories" ADD COLUMN "slug" text NULL;
+-- Backfill "categories" slugs from the names; later duplicates get the row ID appended.
+-- `app migrate up` recomputes them with the application's rules afterwards.
+UPDATE "public"."categories" AS t SET "slug" = s."slug" FROM (
+  SELECT "id", CASE WHEN row_number() OVER (PARTITION BY "base" ORDER BY "id") = 1 THEN "base" ELSE "base" || '-' || "id" END AS "slug"
+  FROM (
+    SELECT "id", COALESCE(NULLIF(trim(BOTH '-' FROM regexp_replace(lower(translate(replace("name", '''', ''), 'ÀÁÂÃÄÅàáâãäåÇçÈÉÊËèéêëÌÍÎÏìíîïÑñÒÓÔÕÖòóôõöÙÚÛÜùúûüÝýÿ', 'AAAAAAaaaaaaCcEEEEeeeeIIIIiiiiNnOOOOOoooooUUUUuuuuYyy')), '[^a-z0-9]+', '-', 'g')), ''), 'category') AS "base"
+    FROM "public"."categories"
+  ) AS b
+) AS s WHERE t."id" = s."id";
+ALTER TABLE "public"."categories" ALTER COLUMN "slug" SET NOT NULL;
+-- Create index "idx_categories_slug" to table: "categories"
+CREATE UNIQUE INDEX "idx_categories_slug" ON "public"."categories" ("slug");
+-- Modify "products" table
+ALTER TABLE "public"."products" ADD COLUMN "slug" text NULL;
+-- Backfill "products" slugs from the names; later duplicates get the row ID appended.
+-- `app migrate up` recomputes them with the application's rules afterwards.
+UPDATE "public"."products" AS t SET "slug" = s."slug" FROM (
+  SELECT "id", CASE WHEN row_number() OVER (PARTITION BY "base" ORDER BY "id") = 1 THEN "base" ELSE "base" || '-' || "id" END AS "slug"
+  FROM (
+    SELECT "id", COALESCE(NULLIF(trim(BOTH '-' FROM regexp_replace(lower(translate(replace("name", '''', ''), 'ÀÁÂÃÄÅàáâãäåÇçÈÉÊËèéêëÌÍÎÏìíîïÑñÒÓÔÕÖòóôõöÙÚÛÜùúûüÝýÿ', 'AAAAAAaaaaaaCcEEEEeeeeIIIIiiiiNnOOOOOoooooUUUUuuuuYyy')), '[^a-z0-9]+', '-', 'g')), ''), 'product') AS "base"
+    FROM "public"."products"
+  ) AS b
+) AS s WHERE t."id" = s."id";
+ALTER TABLE "public"."products" ALTER COLUMN "slug" SET NOT NULL;
+-- Create index "idx_products_slug" to table: "products"
+CREATE UNIQUE INDEX "idx_products_slug" ON "public"."products" ("slug");
+-- Create "slug_redirects" table
+CREATE TABLE "public"."slug_redirects" (
+  "id" bigserial NOT NULL,
+  "entity_type" text NOT NULL,
+  "slug" text NOT NULL,
+  "entity_id" bigint NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_slug_redirects_entity_id" to table: "slug_redirects"
+CREATE INDEX "idx_slug_redirects_entity_id" ON "public"."slug_redirects" ("entity_id");
+-- Create index "idx_slug_redirects_entity_slug" to table: "slug_redirects"
+CREATE UNIQUE INDEX "idx_slug_redirects_entity_slug" ON "public"."slug_redirects" ("entity_type", "slug");
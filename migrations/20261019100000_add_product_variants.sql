@@ -0,0 +1,18 @@
+-- Create "product_variants" table
+CREATE TABLE "public"."product_variants" (
+  "id" bigserial NOT NULL,
+  "product_id" bigint NOT NULL,
+  "sku" text NOT NULL,
+  "options" jsonb NOT NULL DEFAULT '{}',
+  "price" bigint NOT NULL,
+  "stock_quantity" bigint NOT NULL,
+  "is_active" boolean NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_products_variants" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_product_variants_product_id" to table: "product_variants"
+CREATE INDEX "idx_product_variants_product_id" ON "public"."product_variants" ("product_id");
+-- Create index "idx_product_variants_sku" to table: "product_variants"
+CREATE UNIQUE INDEX "idx_product_variants_sku" ON "public"."product_variants" ("sku");
@@ -0,0 +1,28 @@
+-- Create "stock_movements" table
+CREATE TABLE "public"."stock_movements" (
+  "id" bigserial NOT NULL,
+  "product_id" bigint NOT NULL,
+  "variant_id" bigint NULL,
+  "type" text NOT NULL,
+  "quantity" bigint NOT NULL,
+  "balance_after" bigint NOT NULL,
+  "reason" text NOT NULL,
+  "actor" text NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_stock_movements_product" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "fk_stock_movements_variant" FOREIGN KEY ("variant_id") REFERENCES "public"."product_variants" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_stock_movements_product_id" to table: "stock_movements"
+CREATE INDEX "idx_stock_movements_product_id" ON "public"."stock_movements" ("product_id");
+-- Create index "idx_stock_movements_variant_id" to table: "stock_movements"
+CREATE INDEX "idx_stock_movements_variant_id" ON "public"."stock_movements" ("variant_id");
+-- Record the current stock as opening movements so the ledger adds up to it
+INSERT INTO "public"."stock_movements" ("product_id", "type", "quantity", "balance_after", "reason", "actor", "created_at")
+SELECT "id", 'receipt', "stock_quantity", "stock_quantity", 'opening stock', 'system', now() FROM "public"."products" WHERE "stock_quantity" <> 0;
+INSERT INTO "public"."stock_movements" ("product_id", "variant_id", "type", "quantity", "balance_after", "reason", "actor", "created_at")
+SELECT "product_id", "id", 'receipt', "stock_quantity", "stock_quantity", 'opening stock', 'system', now() FROM "public"."product_variants" WHERE "stock_quantity" <> 0;
+-- Modify "products" table
+ALTER TABLE "public"."products" ADD CONSTRAINT "chk_products_stock_quantity" CHECK (stock_quantity >= 0);
+-- Modify "product_variants" table
+ALTER TABLE "public"."product_variants" ADD CONSTRAINT "chk_product_variants_stock_quantity" CHECK (stock_quantity >= 0);
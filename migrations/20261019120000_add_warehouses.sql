@@ -0,0 +1,44 @@
+-- Create "warehouses" table
+CREATE TABLE "public"."warehouses" (
+  "id" bigserial NOT NULL,
+  "code" text NOT NULL,
+  "name" text NOT NULL,
+  "is_default" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_warehouses_code" to table: "warehouses"
+CREATE UNIQUE INDEX "idx_warehouses_code" ON "public"."warehouses" ("code");
+-- Create index "idx_warehouses_default" to table: "warehouses"
+CREATE UNIQUE INDEX "idx_warehouses_default" ON "public"."warehouses" ("is_default") WHERE is_default;
+-- Create the default warehouse that holds all existing stock
+INSERT INTO "public"."warehouses" ("code", "name", "is_default", "created_at", "updated_at") VALUES ('MAIN', 'Main warehouse', true, now(), now());
+-- Create "stock_levels" table
+CREATE TABLE "public"."stock_levels" (
+  "id" bigserial NOT NULL,
+  "warehouse_id" bigint NOT NULL,
+  "product_id" bigint NOT NULL,
+  "variant_id" bigint NOT NULL DEFAULT 0,
+  "quantity" bigint NOT NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_stock_levels_product" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "fk_stock_levels_warehouse" FOREIGN KEY ("warehouse_id") REFERENCES "public"."warehouses" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "chk_stock_levels_quantity" CHECK (quantity >= 0)
+);
+-- Create index "idx_stock_levels_location" to table: "stock_levels"
+CREATE UNIQUE INDEX "idx_stock_levels_location" ON "public"."stock_levels" ("warehouse_id", "product_id", "variant_id");
+-- Create index "idx_stock_levels_product_id" to table: "stock_levels"
+CREATE INDEX "idx_stock_levels_product_id" ON "public"."stock_levels" ("product_id");
+-- Move the current stock into the default warehouse
+INSERT INTO "public"."stock_levels" ("warehouse_id", "product_id", "variant_id", "quantity", "updated_at")
+SELECT w."id", p."id", 0, p."stock_quantity", now() FROM "public"."products" p CROSS JOIN "public"."warehouses" w WHERE w."is_default" AND p."stock_quantity" > 0;
+INSERT INTO "public"."stock_levels" ("warehouse_id", "product_id", "variant_id", "quantity", "updated_at")
+SELECT w."id", v."product_id", v."id", v."stock_quantity", now() FROM "public"."product_variants" v CROSS JOIN "public"."warehouses" w WHERE w."is_default" AND v."stock_quantity" > 0;
+-- Modify "stock_movements" table
+ALTER TABLE "public"."stock_movements" ADD COLUMN "warehouse_id" bigint NULL, ADD CONSTRAINT "fk_stock_movements_warehouse" FOREIGN KEY ("warehouse_id") REFERENCES "public"."warehouses" ("id") ON UPDATE NO ACTION ON DELETE SET NULL;
+-- Create index "idx_stock_movements_warehouse_id" to table: "stock_movements"
+CREATE INDEX "idx_stock_movements_warehouse_id" ON "public"."stock_movements" ("warehouse_id");
+-- Attribute the existing ledger to the default warehouse
+UPDATE "public"."stock_movements" SET "warehouse_id" = (SELECT "id" FROM "public"."warehouses" WHERE "is_default");
@@ -0,0 +1,32 @@
+-- Create "reservations" table
+CREATE TABLE "public"."reservations" (
+  "id" bigserial NOT NULL,
+  "status" text NOT NULL,
+  "reference" text NOT NULL,
+  "actor" text NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_reservations_status_expires_at" to table: "reservations"
+CREATE INDEX "idx_reservations_status_expires_at" ON "public"."reservations" ("status", "expires_at");
+-- Create "reservation_items" table
+CREATE TABLE "public"."reservation_items" (
+  "id" bigserial NOT NULL,
+  "reservation_id" bigint NOT NULL,
+  "product_id" bigint NOT NULL,
+  "variant_id" bigint NULL,
+  "quantity" bigint NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_reservation_items_product" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "fk_reservation_items_variant" FOREIGN KEY ("variant_id") REFERENCES "public"."product_variants" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "fk_reservations_items" FOREIGN KEY ("reservation_id") REFERENCES "public"."reservations" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "chk_reservation_items_quantity" CHECK (quantity > 0)
+);
+-- Create index "idx_reservation_items_product_id" to table: "reservation_items"
+CREATE INDEX "idx_reservation_items_product_id" ON "public"."reservation_items" ("product_id");
+-- Create index "idx_reservation_items_reservation_id" to table: "reservation_items"
+CREATE INDEX "idx_reservation_items_reservation_id" ON "public"."reservation_items" ("reservation_id");
+-- Create index "idx_reservation_items_variant_id" to table: "reservation_items"
+CREATE INDEX "idx_reservation_items_variant_id" ON "public"."reservation_items" ("variant_id");
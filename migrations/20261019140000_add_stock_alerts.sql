@@ -0,0 +1,17 @@
+-- Modify "categories" table
+ALTER TABLE "public"."categories" ADD COLUMN "reorder_threshold" bigint NULL, ADD CONSTRAINT "chk_categories_reorder_threshold" CHECK (reorder_threshold >= 0);
+-- Modify "products" table
+ALTER TABLE "public"."products" ADD COLUMN "reorder_threshold" bigint NULL, ADD CONSTRAINT "chk_products_reorder_threshold" CHECK (reorder_threshold >= 0);
+-- Create "stock_alerts" table
+CREATE TABLE "public"."stock_alerts" (
+  "id" bigserial NOT NULL,
+  "product_id" bigint NOT NULL,
+  "stock_quantity" bigint NOT NULL,
+  "reorder_threshold" bigint NOT NULL,
+  "created_at" timestamptz NULL,
+  "resolved_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_stock_alerts_product" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_stock_alerts_open" to table: "stock_alerts"
+CREATE UNIQUE INDEX "idx_stock_alerts_open" ON "public"."stock_alerts" ("product_id") WHERE (resolved_at IS NULL);
@@ -0,0 +1,22 @@
+-- Create "price_changes" table
+CREATE TABLE "public"."price_changes" (
+  "id" bigserial NOT NULL,
+  "product_id" bigint NOT NULL,
+  "price" bigint NOT NULL,
+  "effective_from" timestamptz NOT NULL,
+  "applied_at" timestamptz NULL,
+  "reason" text NOT NULL,
+  "actor" text NOT NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_price_changes_product" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "chk_price_changes_price" CHECK (price > 0)
+);
+-- Create index "idx_price_changes_pending" to table: "price_changes"
+CREATE INDEX "idx_price_changes_pending" ON "public"."price_changes" ("effective_from") WHERE (applied_at IS NULL);
+-- Create index "idx_price_changes_product_effective_from" to table: "price_changes"
+CREATE INDEX "idx_price_changes_product_effective_from" ON "public"."price_changes" ("product_id", "effective_from");
+-- Start the price history of existing products with their current price
+INSERT INTO "public"."price_changes" ("product_id", "price", "effective_from", "applied_at", "reason", "actor", "created_at")
+SELECT "id", "price", now(), now(), 'initial price', 'system', now()
+FROM "public"."products" WHERE "price" > 0;
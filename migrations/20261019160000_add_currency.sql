@@ -0,0 +1,6 @@
+-- Modify "products" table
+ALTER TABLE "public"."products" ADD COLUMN "currency" text NOT NULL DEFAULT 'IDR';
+-- Modify "product_variants" table
+ALTER TABLE "public"."product_variants" ADD COLUMN "currency" text NOT NULL DEFAULT 'IDR';
+-- Modify "price_changes" table
+ALTER TABLE "public"."price_changes" ADD COLUMN "currency" text NOT NULL DEFAULT 'IDR';
@@ -0,0 +1,41 @@
+-- Create "price_lists" table
+CREATE TABLE "public"."price_lists" (
+  "id" bigserial NOT NULL,
+  "code" text NOT NULL,
+  "name" text NOT NULL,
+  "currency" text NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_price_lists_code" to table: "price_lists"
+CREATE UNIQUE INDEX "idx_price_lists_code" ON "public"."price_lists" ("code");
+-- Create "price_list_items" table
+CREATE TABLE "public"."price_list_items" (
+  "id" bigserial NOT NULL,
+  "price_list_id" bigint NOT NULL,
+  "product_id" bigint NOT NULL,
+  "price" bigint NOT NULL,
+  "currency" text NOT NULL DEFAULT 'IDR',
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_price_list_items_price_list" FOREIGN KEY ("price_list_id") REFERENCES "public"."price_lists" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "fk_price_list_items_product" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "chk_price_list_items_price" CHECK (price > 0)
+);
+-- Create index "idx_price_list_items_list_product" to table: "price_list_items"
+CREATE UNIQUE INDEX "idx_price_list_items_list_product" ON "public"."price_list_items" ("price_list_id", "product_id");
+-- Create index "idx_price_list_items_product_id" to table: "price_list_items"
+CREATE INDEX "idx_price_list_items_product_id" ON "public"."price_list_items" ("product_id");
+-- Create "exchange_rates" table
+CREATE TABLE "public"."exchange_rates" (
+  "id" bigserial NOT NULL,
+  "base_currency" text NOT NULL,
+  "quote_currency" text NOT NULL,
+  "rate" numeric(20,10) NOT NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "chk_exchange_rates_rate" CHECK (rate > 0)
+);
+-- Create index "idx_exchange_rates_pair" to table: "exchange_rates"
+CREATE UNIQUE INDEX "idx_exchange_rates_pair" ON "public"."exchange_rates" ("base_currency", "quote_currency");
@@ -0,0 +1,4 @@
+-- Modify "products" table
+ALTER TABLE "public"."products" ADD COLUMN "search_vector" tsvector NULL GENERATED ALWAYS AS (((setweight(to_tsvector('indonesian'::regconfig, name), 'A'::"char") || setweight(to_tsvector('english'::regconfig, name), 'A'::"char")) || setweight(to_tsvector('indonesian'::regconfig, description), 'B'::"char")) || setweight(to_tsvector('english'::regconfig, description), 'B'::"char")) STORED;
+-- Create index "idx_products_search" to table: "products"
+CREATE INDEX "idx_products_search" ON "public"."products" USING GIN ("search_vector");
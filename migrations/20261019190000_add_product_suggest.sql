@@ -0,0 +1,6 @@
+-- Add new extension "pg_trgm"
+CREATE EXTENSION IF NOT EXISTS "pg_trgm";
+-- Create index "idx_categories_name_trgm" to table: "categories"
+CREATE INDEX "idx_categories_name_trgm" ON "public"."categories" USING GIN ("name" gin_trgm_ops);
+-- Create index "idx_products_name_trgm" to table: "products"
+CREATE INDEX "idx_products_name_trgm" ON "public"."products" USING GIN ("name" gin_trgm_ops);
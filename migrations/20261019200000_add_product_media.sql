@@ -0,0 +1,24 @@
+-- Create "product_media" table
+CREATE TABLE "public"."product_media" (
+  "id" bigserial NOT NULL,
+  "product_id" bigint NOT NULL,
+  "storage_key" text NOT NULL,
+  "thumbnail_key" text NOT NULL,
+  "content_type" text NOT NULL,
+  "size" bigint NOT NULL,
+  "width" bigint NOT NULL,
+  "height" bigint NOT NULL,
+  "position" bigint NOT NULL,
+  "is_primary" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_products_media" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "chk_product_media_position" CHECK (position >= 0)
+);
+-- Create index "idx_product_media_primary" to table: "product_media"
+CREATE UNIQUE INDEX "idx_product_media_primary" ON "public"."product_media" ("product_id") WHERE is_primary;
+-- Create index "idx_product_media_product_id" to table: "product_media"
+CREATE INDEX "idx_product_media_product_id" ON "public"."product_media" ("product_id");
+-- Create index "idx_product_media_storage_key" to table: "product_media"
+CREATE UNIQUE INDEX "idx_product_media_storage_key" ON "public"."product_media" ("storage_key");
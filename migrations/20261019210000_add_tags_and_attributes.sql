@@ -0,0 +1,41 @@
+-- Modify "products" table
+ALTER TABLE "public"."products" ADD COLUMN "attributes" jsonb NOT NULL DEFAULT '{}';
+-- Create index "idx_products_attributes" to table: "products"
+CREATE INDEX "idx_products_attributes" ON "public"."products" USING GIN ("attributes" jsonb_path_ops);
+-- Create "tags" table
+CREATE TABLE "public"."tags" (
+  "id" bigserial NOT NULL,
+  "name" text NOT NULL,
+  "slug" text NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id")
+);
+-- Create index "idx_tags_slug" to table: "tags"
+CREATE UNIQUE INDEX "idx_tags_slug" ON "public"."tags" ("slug");
+-- Create "product_tags" table
+CREATE TABLE "public"."product_tags" (
+  "product_id" bigint NOT NULL,
+  "tag_id" bigint NOT NULL,
+  PRIMARY KEY ("product_id", "tag_id"),
+  CONSTRAINT "fk_product_tags_product" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "fk_product_tags_tag" FOREIGN KEY ("tag_id") REFERENCES "public"."tags" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create "category_attributes" table
+CREATE TABLE "public"."category_attributes" (
+  "id" bigserial NOT NULL,
+  "category_id" bigint NOT NULL,
+  "name" text NOT NULL,
+  "type" text NOT NULL,
+  "options" jsonb NOT NULL DEFAULT '[]',
+  "required" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_category_attributes_category" FOREIGN KEY ("category_id") REFERENCES "public"."categories" ("id") ON UPDATE NO ACTION ON DELETE CASCADE,
+  CONSTRAINT "chk_category_attributes_type" CHECK (type IN ('string', 'number', 'boolean', 'enum'))
+);
+-- Create index "idx_category_attributes_lookup" to table: "category_attributes"
+CREATE INDEX "idx_category_attributes_lookup" ON "public"."category_attributes" ("name");
+-- Create index "idx_category_attributes_name" to table: "category_attributes"
+CREATE UNIQUE INDEX "idx_category_attributes_name" ON "public"."category_attributes" ("category_id", "name");
@@ -0,0 +1,29 @@
+-- Create "product_translations" table
+CREATE TABLE "public"."product_translations" (
+  "product_id" bigint NOT NULL,
+  "locale" text NOT NULL,
+  "name" text NOT NULL,
+  "description" text NOT NULL,
+  "search_vector" tsvector NULL GENERATED ALWAYS AS (setweight(to_tsvector(CASE locale WHEN 'id'::text THEN 'indonesian'::regconfig ELSE 'english'::regconfig END, name), 'A'::"char") || setweight(to_tsvector(CASE locale WHEN 'id'::text THEN 'indonesian'::regconfig ELSE 'english'::regconfig END, description), 'B'::"char")) STORED,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("product_id", "locale"),
+  CONSTRAINT "fk_product_translations_product" FOREIGN KEY ("product_id") REFERENCES "public"."products" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_product_translations_name_trgm" to table: "product_translations"
+CREATE INDEX "idx_product_translations_name_trgm" ON "public"."product_translations" USING GIN ("name" gin_trgm_ops);
+-- Create index "idx_product_translations_search" to table: "product_translations"
+CREATE INDEX "idx_product_translations_search" ON "public"."product_translations" USING GIN ("search_vector");
+-- Create "category_translations" table
+CREATE TABLE "public"."category_translations" (
+  "category_id" bigint NOT NULL,
+  "locale" text NOT NULL,
+  "name" text NOT NULL,
+  "description" text NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("category_id", "locale"),
+  CONSTRAINT "fk_category_translations_category" FOREIGN KEY ("category_id") REFERENCES "public"."categories" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_category_translations_name_trgm" to table: "category_translations"
+CREATE INDEX "idx_category_translations_name_trgm" ON "public"."category_translations" USING GIN ("name" gin_trgm_ops);
@@ -0,0 +1,11 @@
+-- Rename the products whose name another product of the category already
+-- has, ignoring case: every one after the first gets its id appended, as in
+-- "Laptop (42)".
+UPDATE "public"."products" p SET "name" = p."name" || ' (' || p."id" || ')'
+FROM (
+  SELECT "id", row_number() OVER (PARTITION BY "category_id", lower("name") ORDER BY "id") AS n
+  FROM "public"."products"
+) d
+WHERE d."id" = p."id" AND d.n > 1;
+-- Create index "idx_products_category_name" to table: "products"
+CREATE UNIQUE INDEX "idx_products_category_name" ON "public"."products" ("category_id", (lower(name)));
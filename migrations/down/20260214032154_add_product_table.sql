@@ -0,0 +1,2 @@
+-- Drop "products" table
+DROP TABLE "public"."products";
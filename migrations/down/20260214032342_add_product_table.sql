@@ -0,0 +1,2 @@
+-- Modify "products" table
+ALTER TABLE "public"."products" ALTER COLUMN "category_id" DROP NOT NULL;
@@ -0,0 +1,2 @@
+-- Modify "categories" table
+ALTER TABLE "public"."categories" DROP COLUMN "path", DROP COLUMN "parent_id";
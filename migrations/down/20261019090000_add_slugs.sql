@@ -0,0 +1,6 @@
+-- Drop "slug_redirects" table
+DROP TABLE "public"."slug_redirects";
+-- Modify "products" table
+ALTER TABLE "public"."products" DROP COLUMN "slug";
+-- Modify "categories" table
+ALTER TABLE "public"."categories" DROP COLUMN "slug";
@@ -0,0 +1,2 @@
+-- Drop "product_variants" table
+DROP TABLE "public"."product_variants";
@@ -0,0 +1,6 @@
+-- Modify "product_variants" table
+ALTER TABLE "public"."product_variants" DROP CONSTRAINT "chk_product_variants_stock_quantity";
+-- Modify "products" table
+ALTER TABLE "public"."products" DROP CONSTRAINT "chk_products_stock_quantity";
+-- Drop "stock_movements" table
+DROP TABLE "public"."stock_movements";
@@ -0,0 +1,6 @@
+-- Modify "stock_movements" table
+ALTER TABLE "public"."stock_movements" DROP CONSTRAINT "fk_stock_movements_warehouse", DROP COLUMN "warehouse_id";
+-- Drop "stock_levels" table
+DROP TABLE "public"."stock_levels";
+-- Drop "warehouses" table
+DROP TABLE "public"."warehouses";
@@ -0,0 +1,4 @@
+-- Drop "reservation_items" table
+DROP TABLE "public"."reservation_items";
+-- Drop "reservations" table
+DROP TABLE "public"."reservations";
@@ -0,0 +1,6 @@
+-- Drop "stock_alerts" table
+DROP TABLE "public"."stock_alerts";
+-- Modify "products" table
+ALTER TABLE "public"."products" DROP CONSTRAINT "chk_products_reorder_threshold", DROP COLUMN "reorder_threshold";
+-- Modify "categories" table
+ALTER TABLE "public"."categories" DROP CONSTRAINT "chk_categories_reorder_threshold", DROP COLUMN "reorder_threshold";
@@ -0,0 +1,2 @@
+-- Drop "price_changes" table
+DROP TABLE "public"."price_changes";
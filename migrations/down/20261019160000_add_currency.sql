@@ -0,0 +1,6 @@
+-- Modify "price_changes" table
+ALTER TABLE "public"."price_changes" DROP COLUMN "currency";
+-- Modify "product_variants" table
+ALTER TABLE "public"."product_variants" DROP COLUMN "currency";
+-- Modify "products" table
+ALTER TABLE "public"."products" DROP COLUMN "currency";
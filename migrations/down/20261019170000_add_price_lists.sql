@@ -0,0 +1,6 @@
+-- Drop "exchange_rates" table
+DROP TABLE "public"."exchange_rates";
+-- Drop "price_list_items" table
+DROP TABLE "public"."price_list_items";
+-- Drop "price_lists" table
+DROP TABLE "public"."price_lists";
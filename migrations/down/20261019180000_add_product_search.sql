@@ -0,0 +1,4 @@
+-- Drop index "idx_products_search" from table: "products"
+DROP INDEX "public"."idx_products_search";
+-- Modify "products" table
+ALTER TABLE "public"."products" DROP COLUMN "search_vector";
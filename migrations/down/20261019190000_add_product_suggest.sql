@@ -0,0 +1,6 @@
+-- Drop index "idx_products_name_trgm" from table: "products"
+DROP INDEX "public"."idx_products_name_trgm";
+-- Drop index "idx_categories_name_trgm" from table: "categories"
+DROP INDEX "public"."idx_categories_name_trgm";
+-- Drop extension "pg_trgm"
+DROP EXTENSION IF EXISTS "pg_trgm";
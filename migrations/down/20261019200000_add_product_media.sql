@@ -0,0 +1,2 @@
+-- Drop "product_media" table
+DROP TABLE "public"."product_media";
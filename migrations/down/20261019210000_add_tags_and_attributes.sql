@@ -0,0 +1,10 @@
+-- Drop "category_attributes" table
+DROP TABLE "public"."category_attributes";
+-- Drop "product_tags" table
+DROP TABLE "public"."product_tags";
+-- Drop "tags" table
+DROP TABLE "public"."tags";
+-- Drop index "idx_products_attributes" from table: "products"
+DROP INDEX "public"."idx_products_attributes";
+-- Modify "products" table
+ALTER TABLE "public"."products" DROP COLUMN "attributes";
@@ -0,0 +1,4 @@
+-- Drop "category_translations" table
+DROP TABLE "public"."category_translations";
+-- Drop "product_translations" table
+DROP TABLE "public"."product_translations";
@@ -0,0 +1,4 @@
+-- The names the up migration gave duplicate products, suffixed with their
+-- id, are kept: which of them were renamed is not recorded.
+-- Drop index "idx_products_category_name" from table: "products"
+DROP INDEX "public"."idx_products_category_name";